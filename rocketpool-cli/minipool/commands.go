@@ -1,6 +1,8 @@
 package minipool
 
 import (
+    "fmt"
+
     "github.com/urfave/cli"
 
     cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
@@ -31,6 +33,34 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                 },
             },
 
+            cli.Command{
+                Name:      "performance",
+                Aliases:   []string{"p"},
+                Usage:     "Show the duty performance and balance change of the node's minipools over recent epochs",
+                UsageText: "rocketpool minipool performance [options]",
+                Flags: []cli.Flag{
+                    cli.Uint64Flag{
+                        Name:  "epochs, e",
+                        Usage: "The number of recent epochs to report on",
+                        Value: DefaultPerformanceEpochs,
+                    },
+                },
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Validate flags
+                    if c.Uint64("epochs") == 0 {
+                        return fmt.Errorf("Invalid epoch count '%d' - must be a positive integer", c.Uint64("epochs"))
+                    }
+
+                    // Run
+                    return getPerformance(c)
+
+                },
+            },
+
             cli.Command{
                 Name:      "refund",
                 Aliases:   []string{"r"},
@@ -0,0 +1,56 @@
+package minipool
+
+import (
+    "fmt"
+    "math/big"
+
+    "github.com/rocket-pool/rocketpool-go/utils/eth"
+    "github.com/urfave/cli"
+
+    "github.com/rocket-pool/smartnode/shared/services/rocketpool"
+    "github.com/rocket-pool/smartnode/shared/utils/hex"
+    "github.com/rocket-pool/smartnode/shared/utils/math"
+)
+
+
+func getPerformance(c *cli.Context) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Get minipool performance
+    performance, err := rp.MinipoolPerformance(c.Uint64("epochs"))
+    if err != nil {
+        return err
+    }
+
+    // Check minipools
+    if len(performance.Minipools) == 0 {
+        fmt.Println("The node does not have any minipools with validators on the beacon chain yet.")
+        return nil
+    }
+
+    // Print minipool performance
+    fmt.Printf("Minipool performance for epochs %d to %d:\n\n", performance.StartEpoch, performance.EndEpoch)
+    for _, minipool := range performance.Minipools {
+        balanceChange := new(big.Int).Sub(minipool.EndBalance, minipool.StartBalance)
+        fmt.Printf("--------------------\n")
+        fmt.Printf("\n")
+        fmt.Printf("Address:              %s\n", minipool.Address.Hex())
+        fmt.Printf("Validator pubkey:     %s\n", hex.AddPrefix(minipool.ValidatorPubkey.Hex()))
+        fmt.Printf("Effectiveness:        %.2f%%\n", minipool.Effectiveness * 100)
+        fmt.Printf("Missed attestations:  %d of %d\n", minipool.MissedAttestations, minipool.AttestationDuties)
+        fmt.Printf("Missed proposals:     %d of %d\n", minipool.MissedProposals, minipool.ProposalDuties)
+        fmt.Printf("Start balance:        %.6f ETH\n", math.RoundDown(eth.WeiToEth(minipool.StartBalance), 6))
+        fmt.Printf("End balance:          %.6f ETH\n", math.RoundDown(eth.WeiToEth(minipool.EndBalance), 6))
+        fmt.Printf("Balance change:       %+.6f ETH\n", eth.WeiToEth(balanceChange))
+        fmt.Printf("\n")
+    }
+
+    // Return
+    return nil
+
+}
+
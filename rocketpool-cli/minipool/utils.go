@@ -2,5 +2,8 @@ package minipool
 
 
 // Config
-const TimeFormat = "2006-01-02, 15:04 -0700 MST"
+const (
+    TimeFormat = "2006-01-02, 15:04 -0700 MST"
+    DefaultPerformanceEpochs = 10
+)
 
@@ -194,6 +194,22 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                 },
             },
 
+            cli.Command{
+                Name:      "events",
+                Aliases:   []string{"e"},
+                Usage:     "Tail beacon chain events relevant to the node's validators",
+                UsageText: "rocketpool service events",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run command
+                    return serviceEvents(c)
+
+                },
+            },
+
             cli.Command{
                 Name:      "stats",
                 Aliases:   []string{"a"},
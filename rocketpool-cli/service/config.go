@@ -2,6 +2,7 @@ package service
 
 import (
     "fmt"
+    "io/ioutil"
     "math/rand"
     "time"
 
@@ -10,6 +11,14 @@ import (
     "github.com/rocket-pool/smartnode/shared/services/config"
     "github.com/rocket-pool/smartnode/shared/services/rocketpool"
     cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
+    "github.com/rocket-pool/smartnode/shared/utils/eth2"
+)
+
+
+// Config
+const (
+    InterchangeBackupFile = "slashing-protection.json"
+    InterchangeBackupFileMode = 0600
 )
 
 
@@ -31,6 +40,9 @@ func configureService(c *cli.Context) error {
         return err
     }
 
+    // Get current Eth 2.0 client
+    previousEth2Client := userConfig.Chains.Eth2.Client.Selected
+
     // Configure chains
     if err := configureChain(&(globalConfig.Chains.Eth1), &(userConfig.Chains.Eth1), "Eth 1.0", false); err != nil {
         return err
@@ -39,11 +51,40 @@ func configureService(c *cli.Context) error {
         return err
     }
 
+    // Export slashing protection history from the previous Eth 2.0 client if changed
+    // The previous validator client is left stopped so that it cannot sign after its history is exported
+    var interchange []byte
+    if previousEth2Client != "" && previousEth2Client != userConfig.Chains.Eth2.Client.Selected {
+        if cliutils.Confirm("The Eth 2.0 client has changed. Would you like to transfer your validators' slashing protection history to the new client (recommended)?") {
+            fmt.Println("Exporting slashing protection history from the previous client...")
+            data, err := rp.ExportSlashingProtection(getComposeFiles(c), false)
+            if err != nil {
+                return fmt.Errorf("%w; the configuration was not changed, run 'rocketpool service start' to restart the validator client", err)
+            }
+            if _, err := eth2.ParseInterchange(data); err != nil {
+                return err
+            }
+            interchange = data
+        }
+    }
+
     // Save user config
     if err := rp.SaveUserConfig(userConfig); err != nil {
         return err
     }
 
+    // Import slashing protection history into the new Eth 2.0 client
+    // On failure, the history is saved to a file so that it can be imported manually
+    if interchange != nil {
+        fmt.Println("Importing slashing protection history into the new client...")
+        if err := rp.ImportSlashingProtection(getComposeFiles(c), interchange, false); err != nil {
+            if writeErr := ioutil.WriteFile(InterchangeBackupFile, interchange, InterchangeBackupFileMode); writeErr != nil {
+                return fmt.Errorf("%w; the exported history could not be saved: %s", err, writeErr.Error())
+            }
+            return fmt.Errorf("%w; the exported history was saved to %s and can be imported with 'rocketpool wallet slashing-protection import %s'", err, InterchangeBackupFile, InterchangeBackupFile)
+        }
+    }
+
     // Log & return
     fmt.Println("Done! Run 'rocketpool service start' to apply new configuration settings.")
     return nil
@@ -5,7 +5,9 @@ import (
 
     "github.com/urfave/cli"
 
+    "github.com/rocket-pool/smartnode/shared/services/beacon"
     "github.com/rocket-pool/smartnode/shared/services/rocketpool"
+    "github.com/rocket-pool/smartnode/shared/types/api"
     cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
 )
 
@@ -136,6 +138,30 @@ func serviceLogs(c *cli.Context, serviceNames ...string) error {
 }
 
 
+// Tail beacon chain events relevant to the node's validators
+func serviceEvents(c *cli.Context) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Print events
+    fmt.Println("Waiting for beacon chain events...")
+    return rp.StreamNodeEvents(func(event api.NodeEventResponse) {
+        switch event.Topic {
+            case beacon.EventTopicFinalizedCheckpoint:
+                fmt.Printf("Epoch %d finalized (block %s)\n", event.Epoch, event.BlockRoot.Hex())
+            case beacon.EventTopicChainReorg:
+                fmt.Printf("Chain reorg of depth %d at slot %d (new head %s)\n", event.Depth, event.Slot, event.BlockRoot.Hex())
+            case beacon.EventTopicVoluntaryExit:
+                fmt.Printf("Minipool %s validator %d exited at epoch %d\n", event.MinipoolAddress.Hex(), event.ValidatorIndex, event.Epoch)
+        }
+    })
+
+}
+
+
 // View the Rocket Pool service stats
 func serviceStats(c *cli.Context) error {
 
@@ -0,0 +1,126 @@
+package wallet
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/urfave/cli"
+
+    "github.com/rocket-pool/smartnode/shared/services/rocketpool"
+    "github.com/rocket-pool/smartnode/shared/services/wallet"
+    cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
+)
+
+
+func auditWallet(c *cli.Context) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Get & check wallet status
+    status, err := rp.WalletStatus()
+    if err != nil {
+        return err
+    }
+    if !status.WalletInitialized {
+        fmt.Println("The node wallet is not initialized.")
+        return nil
+    }
+
+    // Log
+    fmt.Println("Auditing node validator keys...")
+    fmt.Println("")
+
+    // Audit wallet
+    audit, err := rp.AuditWallet()
+    if err != nil {
+        return err
+    }
+    if len(audit.ValidatorKeys) == 0 {
+        fmt.Println("No validator keys were found.")
+        return nil
+    }
+
+    // Print report
+    problemCount := 0
+    missingCount := 0
+    unverifiedCount := 0
+    for _, key := range audit.ValidatorKeys {
+        if !key.Derived {
+            fmt.Printf("Validator %s:\n", key.Pubkey.Hex())
+            fmt.Println("    Belongs to a minipool but was not found in the node wallet; run 'rocketpool wallet rebuild' to recover it.")
+            fmt.Println("")
+            problemCount++
+            continue
+        }
+        if !key.Minipool {
+            fmt.Printf("Validator %s (index %d):\n", key.Pubkey.Hex(), key.Index)
+            fmt.Println("    Does not belong to a validating minipool; keystores were not checked.")
+            fmt.Println("")
+            continue
+        }
+        fmt.Printf("Validator %s (index %d, path %s):\n", key.Pubkey.Hex(), key.Index, key.DerivationPath)
+        for _, keystoreAudit := range key.Keystores {
+            if keystoreAudit.Detail != "" {
+                fmt.Printf("    %s: %s (%s)\n", keystoreAudit.Keystore, keystoreAudit.Status, keystoreAudit.Detail)
+            } else {
+                fmt.Printf("    %s: %s\n", keystoreAudit.Keystore, keystoreAudit.Status)
+            }
+            if keystoreAudit.Status == wallet.KeyStatusPathUnverified {
+                unverifiedCount++
+            } else if keystoreAudit.Status != wallet.KeyStatusOK {
+                problemCount++
+            }
+            if keystoreAudit.Status == wallet.KeyStatusMissing {
+                missingCount++
+            }
+        }
+        fmt.Println("")
+    }
+
+    // Log result
+    if unverifiedCount > 0 {
+        fmt.Printf("%d keystore entries are valid but their derivation paths could not be verified, as their keystores do not store them.\n", unverifiedCount)
+    }
+    if problemCount == 0 {
+        fmt.Println("All validator keys are present and valid in every keystore.")
+        return nil
+    }
+    fmt.Printf("Found %d problem(s), %d of which are missing keystore entries.\n", problemCount, missingCount)
+
+    // Check for repair
+    if !c.Bool("repair") || missingCount == 0 {
+        if missingCount > 0 {
+            fmt.Println("Run 'rocketpool wallet audit --repair' to restore missing keystore entries.")
+        }
+        return nil
+    }
+
+    // Prompt for confirmation
+    if !(c.Bool("yes") || cliutils.Confirm(fmt.Sprintf("Are you sure you want to restore %d missing keystore entries?", missingCount))) {
+        fmt.Println("Cancelled.")
+        return nil
+    }
+
+    // Repair wallet
+    repair, err := rp.RepairWallet()
+    if err != nil {
+        return err
+    }
+
+    // Log & return
+    fmt.Println("Restored validator keys:")
+    for _, key := range repair.ValidatorKeys {
+        keystores := make([]string, len(key.Keystores))
+        for ki, keystoreAudit := range key.Keystores {
+            keystores[ki] = keystoreAudit.Keystore
+        }
+        fmt.Printf("%s to %s\n", key.Pubkey.Hex(), strings.Join(keystores, ", "))
+    }
+    fmt.Println("Please restart your validator client for the restored keys to be loaded.")
+    return nil
+
+}
+
@@ -1,8 +1,11 @@
 package wallet
 
 import (
+    "fmt"
+
     "github.com/urfave/cli"
 
+    "github.com/rocket-pool/smartnode/shared/services/wallet"
     cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
 )
 
@@ -76,6 +79,11 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                         Name:  "mnemonic, m",
                         Usage: "The mnemonic phrase to recover the wallet from",
                     },
+                    cli.Uint64Flag{
+                        Name:  "search-window, w",
+                        Usage: "The number of key indices to search for validator keys beyond the last key found",
+                        Value: wallet.DefaultValidatorKeyRecoverWindow,
+                    },
                 },
                 Action: func(c *cli.Context) error {
 
@@ -89,6 +97,9 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                     if c.String("mnemonic") != "" {
                         if _, err := cliutils.ValidateWalletMnemonic("mnemonic", c.String("mnemonic")); err != nil { return err }
                     }
+                    if c.Uint64("search-window") == 0 {
+                        return fmt.Errorf("Invalid search window '%d' - must be a positive integer", c.Uint64("search-window"))
+                    }
 
                     // Run
                     return recoverWallet(c)
@@ -112,6 +123,54 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                 },
             },
 
+            cli.Command{
+                Name:      "prune",
+                Aliases:   []string{"u"},
+                Usage:     "Delete validator keys for dissolved, closed or exited minipools",
+                UsageText: "rocketpool wallet prune [options]",
+                Flags: []cli.Flag{
+                    cli.BoolFlag{
+                        Name:  "yes, y",
+                        Usage: "Automatically confirm validator key deletion",
+                    },
+                },
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run
+                    return pruneWallet(c)
+
+                },
+            },
+
+            cli.Command{
+                Name:      "audit",
+                Aliases:   []string{"a"},
+                Usage:     "Check validator keys against minipools and keystores",
+                UsageText: "rocketpool wallet audit [options]",
+                Flags: []cli.Flag{
+                    cli.BoolFlag{
+                        Name:  "repair, r",
+                        Usage: "Restore validator keys missing from keystores",
+                    },
+                    cli.BoolFlag{
+                        Name:  "yes, y",
+                        Usage: "Automatically confirm validator key restoration",
+                    },
+                },
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run
+                    return auditWallet(c)
+
+                },
+            },
+
             cli.Command{
                 Name:      "export",
                 Aliases:   []string{"e"},
@@ -128,6 +187,55 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
                 },
             },
 
+            cli.Command{
+                Name:      "slashing-protection",
+                Aliases:   []string{"p"},
+                Usage:     "Export or import the validator client's slashing protection history in EIP-3076 interchange format",
+                Flags: []cli.Flag{
+                    cli.StringSliceFlag{
+                        Name:  "compose-file, f",
+                        Usage: "Optional compose files to override the standard Rocket Pool docker-compose.yml; this flag may be defined multiple times",
+                    },
+                },
+                Subcommands: []cli.Command{
+
+                    cli.Command{
+                        Name:      "export",
+                        Aliases:   []string{"e"},
+                        Usage:     "Export the selected Eth 2.0 client's slashing protection history to an interchange file",
+                        UsageText: "rocketpool wallet slashing-protection export path",
+                        Action: func(c *cli.Context) error {
+
+                            // Validate args
+                            if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
+                            path := c.Args().Get(0)
+
+                            // Run
+                            return exportSlashingProtection(c, path)
+
+                        },
+                    },
+
+                    cli.Command{
+                        Name:      "import",
+                        Aliases:   []string{"i"},
+                        Usage:     "Import slashing protection history from an interchange file into the selected Eth 2.0 client",
+                        UsageText: "rocketpool wallet slashing-protection import path",
+                        Action: func(c *cli.Context) error {
+
+                            // Validate args
+                            if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
+                            path := c.Args().Get(0)
+
+                            // Run
+                            return importSlashingProtection(c, path)
+
+                        },
+                    },
+
+                },
+            },
+
         },
     })
 }
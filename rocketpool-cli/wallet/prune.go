@@ -0,0 +1,72 @@
+package wallet
+
+import (
+    "fmt"
+
+    rptypes "github.com/rocket-pool/rocketpool-go/types"
+    "github.com/urfave/cli"
+
+    "github.com/rocket-pool/smartnode/shared/services/rocketpool"
+    cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
+)
+
+
+func pruneWallet(c *cli.Context) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Get & check wallet status
+    status, err := rp.WalletStatus()
+    if err != nil {
+        return err
+    }
+    if !status.WalletInitialized {
+        fmt.Println("The node wallet is not initialized.")
+        return nil
+    }
+
+    // Get prunable validator keys
+    prunable, err := rp.GetPrunableValidatorKeys()
+    if err != nil {
+        return err
+    }
+    if len(prunable.ValidatorKeys) == 0 {
+        fmt.Println("No validator keys can be pruned.")
+        return nil
+    }
+
+    // Log
+    fmt.Println("The following validator keys belong to minipools which can no longer attest:")
+    for _, key := range prunable.ValidatorKeys {
+        fmt.Printf("%s (minipool %s, %s)\n", key.Pubkey.Hex(), key.MinipoolAddress.Hex(), key.Reason)
+    }
+    fmt.Println("")
+
+    // Prompt for confirmation
+    if !(c.Bool("yes") || cliutils.Confirm(fmt.Sprintf("Are you sure you want to permanently delete these %d validator key(s)? This action cannot be undone!", len(prunable.ValidatorKeys)))) {
+        fmt.Println("Cancelled.")
+        return nil
+    }
+
+    // Prune wallet
+    pubkeys := make([]rptypes.ValidatorPubkey, len(prunable.ValidatorKeys))
+    for ki, key := range prunable.ValidatorKeys {
+        pubkeys[ki] = key.Pubkey
+    }
+    response, err := rp.PruneWallet(pubkeys)
+    if err != nil {
+        return err
+    }
+
+    // Log & return
+    fmt.Printf("Deleted %d validator key(s):\n", len(response.ValidatorKeys))
+    for _, key := range response.ValidatorKeys {
+        fmt.Println(key.Pubkey.Hex())
+    }
+    return nil
+
+}
+
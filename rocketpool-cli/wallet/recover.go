@@ -51,7 +51,7 @@ func recoverWallet(c *cli.Context) error {
     fmt.Println("Recovering node wallet...")
 
     // Recover wallet
-    response, err := rp.RecoverWallet(mnemonic)
+    response, err := rp.RecoverWallet(mnemonic, c.Uint64("search-window"))
     if err != nil {
         return err
     }
@@ -0,0 +1,78 @@
+package wallet
+
+import (
+    "fmt"
+    "io/ioutil"
+
+    "github.com/urfave/cli"
+
+    "github.com/rocket-pool/smartnode/shared/services/rocketpool"
+    "github.com/rocket-pool/smartnode/shared/utils/eth2"
+)
+
+
+// Config
+const InterchangeFileMode = 0600
+
+
+func exportSlashingProtection(c *cli.Context, path string) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Export slashing protection history
+    fmt.Println("Exporting slashing protection history; the validator client will be stopped temporarily...")
+    data, err := rp.ExportSlashingProtection(c.Parent().StringSlice("compose-file"), true)
+    if err != nil {
+        return err
+    }
+
+    // Check interchange data
+    interchange, err := eth2.ParseInterchange(data)
+    if err != nil {
+        return err
+    }
+
+    // Write interchange file
+    if err := ioutil.WriteFile(path, data, InterchangeFileMode); err != nil {
+        return fmt.Errorf("Could not write slashing protection interchange file to %s: %w", path, err)
+    }
+
+    // Log & return
+    fmt.Printf("Exported slashing protection history for %d validator(s) to %s.\n", len(interchange.Data), path)
+    return nil
+
+}
+
+
+func importSlashingProtection(c *cli.Context, path string) error {
+
+    // Get RP client
+    rp, err := rocketpool.NewClientFromCtx(c)
+    if err != nil { return err }
+    defer rp.Close()
+
+    // Read & check interchange file
+    data, err := ioutil.ReadFile(path)
+    if err != nil {
+        return fmt.Errorf("Could not read slashing protection interchange file at %s: %w", path, err)
+    }
+    interchange, err := eth2.ParseInterchange(data)
+    if err != nil {
+        return err
+    }
+
+    // Import slashing protection history
+    fmt.Println("Importing slashing protection history; the validator client will be stopped temporarily...")
+    if err := rp.ImportSlashingProtection(c.Parent().StringSlice("compose-file"), data, true); err != nil {
+        return err
+    }
+
+    // Log & return
+    fmt.Printf("Imported slashing protection history for %d validator(s) from %s.\n", len(interchange.Data), path)
+    return nil
+
+}
+
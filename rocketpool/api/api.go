@@ -3,11 +3,14 @@ package api
 import (
     "github.com/urfave/cli"
 
+    "github.com/rocket-pool/smartnode/shared/services"
+
     "github.com/rocket-pool/smartnode/rocketpool/api/minipool"
     "github.com/rocket-pool/smartnode/rocketpool/api/network"
     "github.com/rocket-pool/smartnode/rocketpool/api/node"
     "github.com/rocket-pool/smartnode/rocketpool/api/queue"
     "github.com/rocket-pool/smartnode/rocketpool/api/wallet"
+    "github.com/rocket-pool/smartnode/rocketpool/api/watchtower"
 )
 
 
@@ -22,17 +25,23 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
         Subcommands: []cli.Command{},
     }
 
+    // Configure logger before running subcommands
+    command.Before = func(c *cli.Context) error {
+        return services.ConfigureLogger(c)
+    }
+
     // Don't show help message for api errors because of JSON serialisation
     command.OnUsageError = func(context *cli.Context, err error, isSubcommand bool) error {
         return err
     }
 
     // Register subcommands
-    minipool.RegisterSubcommands(&command, "minipool", []string{"m"})
-     network.RegisterSubcommands(&command, "network",  []string{"e"})
-        node.RegisterSubcommands(&command, "node",     []string{"n"})
-       queue.RegisterSubcommands(&command, "queue",    []string{"q"})
-      wallet.RegisterSubcommands(&command, "wallet",   []string{"w"})
+      minipool.RegisterSubcommands(&command, "minipool",   []string{"m"})
+       network.RegisterSubcommands(&command, "network",    []string{"e"})
+          node.RegisterSubcommands(&command, "node",       []string{"n"})
+         queue.RegisterSubcommands(&command, "queue",      []string{"q"})
+        wallet.RegisterSubcommands(&command, "wallet",     []string{"w"})
+    watchtower.RegisterSubcommands(&command, "watchtower", []string{"t"})
 
     // Register CLI command
     app.Commands = append(app.Commands, command)
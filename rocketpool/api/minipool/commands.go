@@ -33,6 +33,25 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
                 },
             },
 
+            cli.Command{
+                Name:      "performance",
+                Aliases:   []string{"p"},
+                Usage:     "Get the duty performance and balance change of the node's minipools over recent epochs",
+                UsageText: "rocketpool api minipool performance epochs",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
+                    epochs, err := cliutils.ValidatePositiveUint("epoch count", c.Args().Get(0))
+                    if err != nil { return err }
+
+                    // Run
+                    api.PrintResponse(getPerformance(c, epochs))
+                    return nil
+
+                },
+            },
+
             cli.Command{
                 Name:      "can-refund",
                 Usage:     "Check whether the node can refund ETH from the minipool",
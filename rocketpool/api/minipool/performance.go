@@ -0,0 +1,134 @@
+package minipool
+
+import (
+    "errors"
+
+    "github.com/rocket-pool/rocketpool-go/minipool"
+    "github.com/rocket-pool/rocketpool-go/types"
+    "github.com/rocket-pool/rocketpool-go/utils/eth"
+    "github.com/urfave/cli"
+
+    "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/types/api"
+    rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
+)
+
+
+func getPerformance(c *cli.Context, epochs uint64) (*api.MinipoolPerformanceResponse, error) {
+
+    // Get services
+    if err := services.RequireNodeRegistered(c); err != nil { return nil, err }
+    if err := services.RequireBeaconClientSynced(c); err != nil { return nil, err }
+    w, err := services.GetWallet(c)
+    if err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+    bc, err := services.GetBeaconClient(c)
+    if err != nil { return nil, err }
+
+    // Response
+    response := api.MinipoolPerformanceResponse{}
+
+    // Get node minipool validators
+    nodeAccount, err := w.GetNodeAccount()
+    if err != nil {
+        return nil, err
+    }
+    addresses, err := minipool.GetNodeMinipoolAddresses(rp, nodeAccount.Address, nil)
+    if err != nil {
+        return nil, err
+    }
+    validators, err := rputils.GetMinipoolValidators(rp, bc, nil, addresses, nil, nil)
+    if err != nil {
+        return nil, err
+    }
+
+    // Get epoch range
+    // Epochs are only included once their attestation inclusion window has been finalized
+    head, err := bc.GetBeaconHead()
+    if err != nil {
+        return nil, err
+    }
+    if head.FinalizedEpoch < 2 {
+        return nil, errors.New("The beacon chain has not finalized enough epochs to report performance")
+    }
+    response.EndEpoch = head.FinalizedEpoch - 2
+    if epochs <= response.EndEpoch {
+        response.StartEpoch = response.EndEpoch - epochs + 1
+    }
+
+    // Get validator pubkeys
+    pubkeys := []types.ValidatorPubkey{}
+    for _, address := range addresses {
+        validator := validators[address]
+        if !validator.Exists { continue }
+        pubkeys = append(pubkeys, validator.Pubkey)
+    }
+    if len(pubkeys) == 0 {
+        response.Minipools = []api.MinipoolPerformance{}
+        return &response, nil
+    }
+
+    // Get validator balances at the start of the range and the end of the last epoch
+    balances, err := bc.GetValidatorBalanceHistory(pubkeys, response.StartEpoch, response.EndEpoch + 1)
+    if err != nil {
+        return nil, err
+    }
+
+    // Get validator duty performance by epoch
+    attestationDuties := make(map[types.ValidatorPubkey]uint64)
+    missedAttestations := make(map[types.ValidatorPubkey]uint64)
+    inclusionScores := make(map[types.ValidatorPubkey]float64)
+    proposalDuties := make(map[types.ValidatorPubkey]uint64)
+    missedProposals := make(map[types.ValidatorPubkey]uint64)
+    for epoch := response.StartEpoch; epoch <= response.EndEpoch; epoch++ {
+        performance, err := bc.GetValidatorPerformance(pubkeys, epoch)
+        if err != nil {
+            return nil, err
+        }
+        for pubkey, vp := range performance {
+            if vp.AttestationDuty {
+                attestationDuties[pubkey]++
+                if vp.AttestationIncluded && vp.InclusionDistance > 0 {
+                    inclusionScores[pubkey] += 1 / float64(vp.InclusionDistance)
+                } else {
+                    missedAttestations[pubkey]++
+                }
+            }
+            proposalDuties[pubkey] += uint64(len(vp.ProposalSlots))
+            missedProposals[pubkey] += uint64(len(vp.MissedProposalSlots))
+        }
+    }
+
+    // Build minipool performance
+    // Effectiveness is the mean reciprocal attestation inclusion distance, with missed attestations scoring zero
+    response.Minipools = make([]api.MinipoolPerformance, 0, len(pubkeys))
+    for _, address := range addresses {
+        validator := validators[address]
+        if !validator.Exists { continue }
+        pubkey := validator.Pubkey
+        performance := api.MinipoolPerformance{
+            Address: address,
+            ValidatorPubkey: pubkey,
+            AttestationDuties: attestationDuties[pubkey],
+            MissedAttestations: missedAttestations[pubkey],
+            ProposalDuties: proposalDuties[pubkey],
+            MissedProposals: missedProposals[pubkey],
+            StartBalance: eth.GweiToWei(0),
+            EndBalance: eth.GweiToWei(0),
+        }
+        if attestationDuties[pubkey] > 0 {
+            performance.Effectiveness = inclusionScores[pubkey] / float64(attestationDuties[pubkey])
+        }
+        if history := balances[pubkey]; len(history) > 0 {
+            performance.StartBalance = eth.GweiToWei(float64(history[0]))
+            performance.EndBalance = eth.GweiToWei(float64(history[len(history) - 1]))
+        }
+        response.Minipools = append(response.Minipools, performance)
+    }
+
+    // Return response
+    return &response, nil
+
+}
+
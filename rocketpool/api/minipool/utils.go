@@ -95,7 +95,7 @@ func getNodeMinipoolDetails(rp *rocketpool.RocketPool, bc beacon.Client, nodeAdd
     }
 
     // Get minipool validator statuses
-    validators, err := rputils.GetMinipoolValidators(rp, bc, addresses, nil, nil)
+    validators, err := rputils.GetMinipoolValidators(rp, bc, nil, addresses, nil, nil)
     if err != nil {
         return []api.MinipoolDetails{}, err
     }
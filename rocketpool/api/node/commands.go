@@ -33,6 +33,24 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
                 },
             },
 
+            cli.Command{
+                Name:      "events",
+                Usage:     "Stream beacon chain events relevant to the node's validators",
+                UsageText: "rocketpool api node events",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run
+                    if err := streamEvents(c); err != nil {
+                        api.PrintErrorResponse(err)
+                    }
+                    return nil
+
+                },
+            },
+
             cli.Command{
                 Name:      "can-register",
                 Usage:     "Check whether the node can be registered with Rocket Pool",
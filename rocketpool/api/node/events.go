@@ -0,0 +1,101 @@
+package node
+
+import (
+    "errors"
+
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/rocket-pool/rocketpool-go/minipool"
+    "github.com/urfave/cli"
+
+    "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/services/beacon"
+    "github.com/rocket-pool/smartnode/shared/types/api"
+    apiutils "github.com/rocket-pool/smartnode/shared/utils/api"
+    rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
+)
+
+
+// Streamed event topics
+// Voluntary exits are only streamed for the node's validators
+var streamEventTopics = []string{
+    beacon.EventTopicFinalizedCheckpoint,
+    beacon.EventTopicChainReorg,
+    beacon.EventTopicVoluntaryExit,
+}
+
+
+// Stream beacon chain events, printing a response for each until the subscription fails
+func streamEvents(c *cli.Context) error {
+
+    // Get services
+    if err := services.RequireNodeRegistered(c); err != nil { return err }
+    if err := services.RequireBeaconClientSynced(c); err != nil { return err }
+    w, err := services.GetWallet(c)
+    if err != nil { return err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return err }
+    bc, err := services.GetBeaconClient(c)
+    if err != nil { return err }
+
+    // Get node minipool validators
+    nodeAccount, err := w.GetNodeAccount()
+    if err != nil {
+        return err
+    }
+    addresses, err := minipool.GetNodeMinipoolAddresses(rp, nodeAccount.Address, nil)
+    if err != nil {
+        return err
+    }
+    validators, err := rputils.GetMinipoolValidators(rp, bc, nil, addresses, nil, nil)
+    if err != nil {
+        return err
+    }
+
+    // Get minipool addresses by validator index
+    minipoolAddresses := make(map[uint64]common.Address)
+    for _, address := range addresses {
+        validator := validators[address]
+        if !validator.Exists { continue }
+        validatorIndex, err := bc.GetValidatorIndex(validator.Pubkey)
+        if err != nil {
+            return err
+        }
+        minipoolAddresses[validatorIndex] = address
+    }
+
+    // Subscribe to events
+    events := make(chan beacon.Event)
+    sub, err := bc.SubscribeEvents(streamEventTopics, events)
+    if err != nil {
+        return err
+    }
+    defer sub.Unsubscribe()
+
+    // Print events
+    for {
+        select {
+            case err := <-sub.Err():
+                if err == nil {
+                    err = errors.New("Beacon event subscription closed")
+                }
+                return err
+            case e := <-events:
+                response := api.NodeEventResponse{
+                    Topic: e.Topic,
+                    Slot: e.Slot,
+                    Epoch: e.Epoch,
+                    BlockRoot: e.BlockRoot,
+                    Depth: e.Depth,
+                    ValidatorIndex: e.ValidatorIndex,
+                }
+                if e.Topic == beacon.EventTopicVoluntaryExit {
+                    address, ok := minipoolAddresses[e.ValidatorIndex]
+                    if !ok { continue }
+                    response.MinipoolAddress = address
+                }
+                apiutils.PrintResponse(&response, nil)
+        }
+    }
+
+}
+
@@ -0,0 +1,168 @@
+package wallet
+
+import (
+    "sort"
+
+    "github.com/rocket-pool/rocketpool-go/minipool"
+    rptypes "github.com/rocket-pool/rocketpool-go/types"
+    "github.com/urfave/cli"
+
+    "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/services/wallet"
+    "github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+
+func auditWallet(c *cli.Context) (*api.AuditWalletResponse, error) {
+
+    // Response
+    response := api.AuditWalletResponse{}
+
+    // Audit validator keys
+    audits, err := getValidatorKeyAudits(c)
+    if err != nil {
+        return nil, err
+    }
+    response.ValidatorKeys = audits
+
+    // Return response
+    return &response, nil
+
+}
+
+
+func repairWallet(c *cli.Context) (*api.RepairWalletResponse, error) {
+
+    // Get services
+    w, err := services.GetWallet(c)
+    if err != nil { return nil, err }
+
+    // Response
+    response := api.RepairWalletResponse{}
+
+    // Audit validator keys
+    audits, err := getValidatorKeyAudits(c)
+    if err != nil {
+        return nil, err
+    }
+
+    // Restore validator keys missing from keystores; other failures are left for manual inspection
+    response.ValidatorKeys = []api.ValidatorKeyAudit{}
+    for _, audit := range audits {
+        missing := []api.KeystoreKeyAudit{}
+        keystoreNames := []string{}
+        for _, keystoreAudit := range audit.Keystores {
+            if keystoreAudit.Status == wallet.KeyStatusMissing {
+                missing = append(missing, keystoreAudit)
+                keystoreNames = append(keystoreNames, keystoreAudit.Keystore)
+            }
+        }
+        if len(missing) == 0 { continue }
+        if err := w.RepairValidatorKey(audit.Index, keystoreNames); err != nil {
+            return nil, err
+        }
+        audit.Keystores = missing
+        response.ValidatorKeys = append(response.ValidatorKeys, audit)
+    }
+
+    // Return response
+    return &response, nil
+
+}
+
+
+// Audit derived validator keys against the node's on-chain minipool pubkeys and each keystore
+// Only keys belonging to a minipool are checked in keystores
+func getValidatorKeyAudits(c *cli.Context) ([]api.ValidatorKeyAudit, error) {
+
+    // Get services
+    if err := services.RequireNodeWallet(c); err != nil { return nil, err }
+    if err := services.RequireRocketStorage(c); err != nil { return nil, err }
+    w, err := services.GetWallet(c)
+    if err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+
+    // Get node account
+    nodeAccount, err := w.GetNodeAccount()
+    if err != nil {
+        return nil, err
+    }
+
+    // Get node's validating pubkeys
+    minipoolPubkeys, err := minipool.GetNodeValidatingMinipoolPubkeys(rp, nodeAccount.Address, nil)
+    if err != nil {
+        return nil, err
+    }
+    hasMinipool := make(map[rptypes.ValidatorPubkey]bool, len(minipoolPubkeys))
+    for _, pubkey := range minipoolPubkeys {
+        hasMinipool[pubkey] = true
+    }
+
+    // Derive validator keys
+    keyCount, err := w.GetValidatorKeyCount()
+    if err != nil {
+        return nil, err
+    }
+    derived := make(map[rptypes.ValidatorPubkey]bool, keyCount)
+    audits := []api.ValidatorKeyAudit{}
+    minipoolIndices := []uint{}
+    var index uint
+    for index = 0; index < keyCount; index++ {
+        key, err := w.GetValidatorKeyAt(index)
+        if err != nil {
+            return nil, err
+        }
+        pubkey := rptypes.BytesToValidatorPubkey(key.PublicKey().Marshal())
+        derived[pubkey] = true
+        if hasMinipool[pubkey] {
+            minipoolIndices = append(minipoolIndices, index)
+            continue
+        }
+        audits = append(audits, api.ValidatorKeyAudit{
+            Pubkey: pubkey,
+            Index: index,
+            Derived: true,
+        })
+    }
+
+    // Audit keys with minipools against keystores
+    keyAudits, err := w.AuditValidatorKeys(minipoolIndices)
+    if err != nil {
+        return nil, err
+    }
+    for _, keyAudit := range keyAudits {
+        audit := api.ValidatorKeyAudit{
+            Pubkey: keyAudit.Pubkey,
+            Index: keyAudit.Index,
+            DerivationPath: keyAudit.DerivationPath,
+            Derived: true,
+            Minipool: true,
+            Keystores: []api.KeystoreKeyAudit{},
+        }
+        for name, keystoreAudit := range keyAudit.Keystores {
+            audit.Keystores = append(audit.Keystores, api.KeystoreKeyAudit{
+                Keystore: name,
+                Status: keystoreAudit.Status,
+                Detail: keystoreAudit.Detail,
+            })
+        }
+        sort.Slice(audit.Keystores, func(i, j int) bool { return audit.Keystores[i].Keystore < audit.Keystores[j].Keystore })
+        audits = append(audits, audit)
+    }
+    sort.Slice(audits, func(i, j int) bool { return audits[i].Index < audits[j].Index })
+
+    // Add minipool pubkeys which were not derived from the wallet
+    for _, pubkey := range minipoolPubkeys {
+        if derived[pubkey] { continue }
+        audits = append(audits, api.ValidatorKeyAudit{
+            Pubkey: pubkey,
+            Minipool: true,
+        })
+    }
+
+    // Return
+    return audits, nil
+
+}
+
@@ -73,16 +73,18 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
                 Name:      "recover",
                 Aliases:   []string{"r"},
                 Usage:     "Recover a node wallet from a mnemonic phrase",
-                UsageText: "rocketpool api wallet recover mnemonic",
+                UsageText: "rocketpool api wallet recover mnemonic search-window",
                 Action: func(c *cli.Context) error {
 
                     // Validate args
-                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
+                    if err := cliutils.ValidateArgCount(c, 2); err != nil { return err }
                     mnemonic, err := cliutils.ValidateWalletMnemonic("mnemonic", c.Args().Get(0))
                     if err != nil { return err }
+                    searchWindow, err := cliutils.ValidatePositiveUint("search window", c.Args().Get(1))
+                    if err != nil { return err }
 
                     // Run
-                    api.PrintResponse(recoverWallet(c, mnemonic))
+                    api.PrintResponse(recoverWallet(c, mnemonic, uint(searchWindow)))
                     return nil
 
                 },
@@ -105,6 +107,74 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
                 },
             },
 
+            cli.Command{
+                Name:      "get-prunable-keys",
+                Aliases:   []string{"g"},
+                Usage:     "Get stored validator keys for dissolved, closed or exited minipools",
+                UsageText: "rocketpool api wallet get-prunable-keys",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run
+                    api.PrintResponse(getPrunableValidatorKeys(c))
+                    return nil
+
+                },
+            },
+            cli.Command{
+                Name:      "prune",
+                Aliases:   []string{"u"},
+                Usage:     "Delete stored validator keys for dissolved, closed or exited minipools; only the listed keys are deleted",
+                UsageText: "rocketpool api wallet prune pubkeys",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 1); err != nil { return err }
+                    pubkeys, err := cliutils.ValidateValidatorPubkeys("validator pubkeys", c.Args().Get(0))
+                    if err != nil { return err }
+
+                    // Run
+                    api.PrintResponse(pruneWallet(c, pubkeys))
+                    return nil
+
+                },
+            },
+
+            cli.Command{
+                Name:      "audit",
+                Aliases:   []string{"a"},
+                Usage:     "Audit validator keys against minipools and keystores",
+                UsageText: "rocketpool api wallet audit",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run
+                    api.PrintResponse(auditWallet(c))
+                    return nil
+
+                },
+            },
+            cli.Command{
+                Name:      "repair",
+                Aliases:   []string{"x"},
+                Usage:     "Restore validator keys missing from keystores",
+                UsageText: "rocketpool api wallet repair",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run
+                    api.PrintResponse(repairWallet(c))
+                    return nil
+
+                },
+            },
+
             cli.Command{
                 Name:      "export",
                 Aliases:   []string{"e"},
@@ -0,0 +1,177 @@
+package wallet
+
+import (
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/rocket-pool/rocketpool-go/minipool"
+    rptypes "github.com/rocket-pool/rocketpool-go/types"
+    "github.com/urfave/cli"
+    "golang.org/x/sync/errgroup"
+
+    "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+
+// Reasons validator keys can be pruned
+const (
+    PruneReasonDissolved = "dissolved"
+    PruneReasonClosed = "closed"
+    PruneReasonExited = "exited"
+)
+
+
+func getPrunableValidatorKeys(c *cli.Context) (*api.GetPrunableValidatorKeysResponse, error) {
+
+    // Response
+    response := api.GetPrunableValidatorKeysResponse{}
+
+    // Get prunable keys
+    keys, err := findPrunableValidatorKeys(c)
+    if err != nil {
+        return nil, err
+    }
+    response.ValidatorKeys = keys
+
+    // Return response
+    return &response, nil
+
+}
+
+
+func pruneWallet(c *cli.Context, pubkeys []rptypes.ValidatorPubkey) (*api.PruneWalletResponse, error) {
+
+    // Get services
+    w, err := services.GetWallet(c)
+    if err != nil { return nil, err }
+
+    // Response
+    response := api.PruneWalletResponse{}
+
+    // Get prunable keys
+    keys, err := findPrunableValidatorKeys(c)
+    if err != nil {
+        return nil, err
+    }
+
+    // Delete confirmed keys which are still prunable
+    confirmed := make(map[rptypes.ValidatorPubkey]bool, len(pubkeys))
+    for _, pubkey := range pubkeys {
+        confirmed[pubkey] = true
+    }
+    response.ValidatorKeys = []api.PrunableValidatorKey{}
+    for _, key := range keys {
+        if !confirmed[key.Pubkey] { continue }
+        if err := w.DeleteValidatorKey(key.Pubkey); err != nil {
+            return nil, err
+        }
+        response.ValidatorKeys = append(response.ValidatorKeys, key)
+    }
+
+    // Return response
+    return &response, nil
+
+}
+
+
+// Find stored validator keys which can never attest again
+// Keys are prunable if their minipool was dissolved or closed, or their validator has exited; pending keys are never prunable
+func findPrunableValidatorKeys(c *cli.Context) ([]api.PrunableValidatorKey, error) {
+
+    // Get services
+    if err := services.RequireNodeWallet(c); err != nil { return nil, err }
+    if err := services.RequireRocketStorage(c); err != nil { return nil, err }
+    if err := services.RequireBeaconClientSynced(c); err != nil { return nil, err }
+    w, err := services.GetWallet(c)
+    if err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+    bc, err := services.GetBeaconClient(c)
+    if err != nil { return nil, err }
+
+    // Get stored validator keys, excluding pending keys
+    storedPubkeys, err := w.GetStoredValidatorKeys()
+    if err != nil {
+        return nil, err
+    }
+    pendingKeys, err := w.GetPendingValidatorKeys()
+    if err != nil {
+        return nil, err
+    }
+    pending := make(map[rptypes.ValidatorPubkey]bool, len(pendingKeys))
+    for _, key := range pendingKeys {
+        pending[rptypes.BytesToValidatorPubkey(key.PublicKey().Marshal())] = true
+    }
+    pubkeys := []rptypes.ValidatorPubkey{}
+    for _, pubkey := range storedPubkeys {
+        if !pending[pubkey] {
+            pubkeys = append(pubkeys, pubkey)
+        }
+    }
+    if len(pubkeys) == 0 {
+        return []api.PrunableValidatorKey{}, nil
+    }
+
+    // Get minipool addresses, existence & statuses
+    addresses := make([]common.Address, len(pubkeys))
+    exists := make([]bool, len(pubkeys))
+    statuses := make([]rptypes.MinipoolStatus, len(pubkeys))
+    var wg errgroup.Group
+    for pi, pubkey := range pubkeys {
+        pi, pubkey := pi, pubkey
+        wg.Go(func() error {
+            address, err := minipool.GetMinipoolByPubkey(rp, pubkey, nil)
+            if err != nil || address == (common.Address{}) {
+                return err
+            }
+            addresses[pi] = address
+            if exists[pi], err = minipool.GetMinipoolExists(rp, address, nil); err != nil || !exists[pi] {
+                return err
+            }
+            mp, err := minipool.NewMinipool(rp, address)
+            if err != nil {
+                return err
+            }
+            statuses[pi], err = mp.GetStatus(nil)
+            return err
+        })
+    }
+    if err := wg.Wait(); err != nil {
+        return nil, err
+    }
+
+    // Get validator statuses & current epoch
+    validators, err := bc.GetValidatorStatuses(pubkeys, nil)
+    if err != nil {
+        return nil, err
+    }
+    head, err := bc.GetBeaconHead()
+    if err != nil {
+        return nil, err
+    }
+
+    // Get prunable keys; keys with no minipool are left in place
+    keys := []api.PrunableValidatorKey{}
+    for pi, pubkey := range pubkeys {
+        if addresses[pi] == (common.Address{}) { continue }
+        var reason string
+        if !exists[pi] {
+            reason = PruneReasonClosed
+        } else if statuses[pi] == rptypes.Dissolved {
+            reason = PruneReasonDissolved
+        } else if validator := validators[pubkey]; validator.Exists && validator.ExitEpoch <= head.Epoch {
+            reason = PruneReasonExited
+        } else {
+            continue
+        }
+        keys = append(keys, api.PrunableValidatorKey{
+            Pubkey: pubkey,
+            MinipoolAddress: addresses[pi],
+            Reason: reason,
+        })
+    }
+
+    // Return
+    return keys, nil
+
+}
+
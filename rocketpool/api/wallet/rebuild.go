@@ -5,6 +5,7 @@ import (
     "github.com/urfave/cli"
 
     "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/services/wallet"
     "github.com/rocket-pool/smartnode/shared/types/api"
 )
 
@@ -35,11 +36,14 @@ func rebuildWallet(c *cli.Context) (*api.RebuildWalletResponse, error) {
     }
     response.ValidatorKeys = pubkeys
 
+    // Rebuild validator key index
+    if err := w.RebuildValidatorKeyIndex(); err != nil {
+        return nil, err
+    }
+
     // Recover validator keys
-    for _, pubkey := range pubkeys {
-        if err := w.RecoverValidatorKey(pubkey); err != nil {
-            return nil, err
-        }
+    if err := w.RecoverValidatorKeys(pubkeys, wallet.DefaultValidatorKeyRecoverWindow); err != nil {
+        return nil, err
     }
 
     // Save wallet
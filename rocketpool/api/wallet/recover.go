@@ -11,7 +11,7 @@ import (
 )
 
 
-func recoverWallet(c *cli.Context, mnemonic string) (*api.RecoverWalletResponse, error) {
+func recoverWallet(c *cli.Context, mnemonic string, searchWindow uint) (*api.RecoverWalletResponse, error) {
 
     // Get services
     if err := services.RequireNodePassword(c); err != nil { return nil, err }
@@ -49,10 +49,8 @@ func recoverWallet(c *cli.Context, mnemonic string) (*api.RecoverWalletResponse,
     response.ValidatorKeys = pubkeys
 
     // Recover validator keys
-    for _, pubkey := range pubkeys {
-        if err := w.RecoverValidatorKey(pubkey); err != nil {
-            return nil, err
-        }
+    if err := w.RecoverValidatorKeys(pubkeys, searchWindow); err != nil {
+        return nil, err
     }
 
     // Save wallet
@@ -0,0 +1,39 @@
+package watchtower
+
+import (
+    "github.com/urfave/cli"
+
+    "github.com/rocket-pool/smartnode/shared/utils/api"
+    cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
+)
+
+
+// Register subcommands
+func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
+    command.Subcommands = append(command.Subcommands, cli.Command{
+        Name:      name,
+        Aliases:   aliases,
+        Usage:     "Manage the Rocket Pool watchtower",
+        Subcommands: []cli.Command{
+
+            cli.Command{
+                Name:      "history",
+                Aliases:   []string{"h"},
+                Usage:     "Get the watchtower transaction submission history",
+                UsageText: "rocketpool api watchtower history",
+                Action: func(c *cli.Context) error {
+
+                    // Validate args
+                    if err := cliutils.ValidateArgCount(c, 0); err != nil { return err }
+
+                    // Run
+                    api.PrintResponse(getHistory(c))
+                    return nil
+
+                },
+            },
+
+        },
+    })
+}
+
@@ -0,0 +1,31 @@
+package watchtower
+
+import (
+    "github.com/urfave/cli"
+
+    "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+
+func getHistory(c *cli.Context) (*api.WatchtowerHistoryResponse, error) {
+
+    // Get services
+    db, err := services.GetDatabase(c)
+    if err != nil { return nil, err }
+
+    // Response
+    response := api.WatchtowerHistoryResponse{}
+
+    // Get submissions
+    submissions, err := db.GetSubmissions()
+    if err != nil {
+        return nil, err
+    }
+    response.Submissions = submissions
+
+    // Return response
+    return &response, nil
+
+}
+
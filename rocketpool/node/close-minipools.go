@@ -0,0 +1,187 @@
+package node
+
+import (
+    "math/big"
+
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/ethclient"
+    "github.com/rocket-pool/rocketpool-go/minipool"
+    "github.com/rocket-pool/rocketpool-go/rocketpool"
+    "github.com/rocket-pool/rocketpool-go/types"
+    "github.com/rocket-pool/rocketpool-go/utils/eth"
+    "github.com/urfave/cli"
+    "golang.org/x/sync/errgroup"
+
+    "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/services/metrics"
+    "github.com/rocket-pool/smartnode/shared/services/wallet"
+    "github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+
+// Close minipools task
+type closeMinipools struct {
+    c *cli.Context
+    log log.Logger
+    w *wallet.Wallet
+    ec *ethclient.Client
+    rp *rocketpool.RocketPool
+    maxGasPrice *big.Int
+}
+
+
+// Create close minipools task
+func newCloseMinipools(c *cli.Context, logger log.Logger) (*closeMinipools, error) {
+
+    // Get services
+    cfg, err := services.GetConfig(c)
+    if err != nil { return nil, err }
+    w, err := services.GetWallet(c)
+    if err != nil { return nil, err }
+    ec, err := services.GetEthClient(c)
+    if err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+
+    // Get settings
+    maxGasPrice, err := cfg.GetAutoMaxGasPrice()
+    if err != nil { return nil, err }
+
+    // Return task
+    return &closeMinipools{
+        c: c,
+        log: logger,
+        w: w,
+        ec: ec,
+        rp: rp,
+        maxGasPrice: maxGasPrice,
+    }, nil
+
+}
+
+
+// Close minipools
+func (t *closeMinipools) run() error {
+
+    // Wait for eth client to sync
+    if err := services.WaitEthClientSynced(t.c, true); err != nil {
+        return err
+    }
+
+    // Log
+    t.log.Info("Checking for minipools to close...")
+
+    // Get node account
+    nodeAccount, err := t.w.GetNodeAccount()
+    if err != nil {
+        return err
+    }
+
+    // Get dissolved minipools
+    minipools, err := t.getDissolvedMinipools(nodeAccount.Address)
+    if err != nil {
+        return err
+    }
+    if len(minipools) == 0 {
+        return nil
+    }
+
+    // Log
+    t.log.Info("Minipools are dissolved and will be closed...", "count", len(minipools))
+
+    // Close minipools
+    for _, mp := range minipools {
+        if err := t.closeMinipool(mp); err != nil {
+            t.log.Error("Could not close minipool", "minipool", mp.Address.Hex(), "error", err)
+        }
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Get dissolved minipools
+func (t *closeMinipools) getDissolvedMinipools(nodeAddress common.Address) ([]*minipool.Minipool, error) {
+
+    // Get node minipools
+    minipools, err := getNodeMinipools(t.rp, nodeAddress)
+    if err != nil {
+        return []*minipool.Minipool{}, err
+    }
+
+    // Data
+    var wg errgroup.Group
+    statuses := make([]types.MinipoolStatus, len(minipools))
+
+    // Load minipool statuses
+    for mi, mp := range minipools {
+        mi, mp := mi, mp
+        wg.Go(func() error {
+            status, err := mp.GetStatus(nil)
+            if err == nil { statuses[mi] = status }
+            return err
+        })
+    }
+
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return []*minipool.Minipool{}, err
+    }
+
+    // Filter minipools by status
+    dissolvedMinipools := []*minipool.Minipool{}
+    for mi, mp := range minipools {
+        if statuses[mi] == types.Dissolved {
+            dissolvedMinipools = append(dissolvedMinipools, mp)
+        }
+    }
+
+    // Return
+    return dissolvedMinipools, nil
+
+}
+
+
+// Close a minipool
+func (t *closeMinipools) closeMinipool(mp *minipool.Minipool) error {
+
+    // Log
+    t.log.Info("Closing minipool...", "minipool", mp.Address.Hex())
+
+    // Lock transactions
+    txLock.Lock()
+    defer txLock.Unlock()
+
+    // Get transactor
+    opts, err := t.w.GetNodeAccountTransactor()
+    if err != nil {
+        return err
+    }
+
+    // Check gas price
+    gasPrice, ok, err := checkAutoGasPrice(t.ec, opts, t.maxGasPrice)
+    if err != nil {
+        return err
+    }
+    if !ok {
+        t.log.Info("Gas price is above the maximum for automatic transactions; minipool will not be closed yet.", "minipool", mp.Address.Hex(), "gasPriceGwei", eth.WeiToGwei(gasPrice), "maxGasPriceGwei", eth.WeiToGwei(t.maxGasPrice))
+        return nil
+    }
+
+    // Close
+    txReceipt, err := mp.Close(opts)
+    metrics.RecordTransaction("closeMinipools", err)
+    if err != nil {
+        return err
+    }
+
+    // Log
+    t.log.Info("Successfully closed minipool.", "minipool", mp.Address.Hex(), "txHash", txReceipt.TxHash.Hex())
+
+    // Return
+    return nil
+
+}
+
@@ -1,20 +1,44 @@
 package node
 
 import (
+    "sync"
+    "time"
+
+    "github.com/ethereum/go-ethereum/core/types"
     "github.com/fatih/color"
     "github.com/urfave/cli"
 
     "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/services/beacon"
+    "github.com/rocket-pool/smartnode/shared/services/events"
+    "github.com/rocket-pool/smartnode/shared/services/metrics"
     "github.com/rocket-pool/smartnode/shared/utils/log"
+    "github.com/rocket-pool/smartnode/shared/utils/scheduler"
 )
 
 
 // Config
+var updateMetricsSettings = scheduler.TaskSettings{Interval: 1 * time.Minute, Timeout: 5 * time.Minute}
+var stakePrelaunchMinipoolsSettings = scheduler.TaskSettings{Interval: 5 * time.Minute, Jitter: 10 * time.Second, Timeout: 15 * time.Minute, MinTriggerInterval: 1 * time.Minute}
+var refundMinipoolsSettings = scheduler.TaskSettings{Interval: 5 * time.Minute, Jitter: 30 * time.Second, Timeout: 10 * time.Minute}
+var withdrawMinipoolsSettings = scheduler.TaskSettings{Interval: 5 * time.Minute, Jitter: 30 * time.Second, Timeout: 10 * time.Minute}
+var closeMinipoolsSettings = scheduler.TaskSettings{Interval: 5 * time.Minute, Jitter: 30 * time.Second, Timeout: 10 * time.Minute}
 const (
     StakePrelaunchMinipoolsColor = color.FgBlue
+    RefundMinipoolsColor = color.FgYellow
+    WithdrawMinipoolsColor = color.FgCyan
+    CloseMinipoolsColor = color.FgMagenta
+    EventsColor = color.FgWhite
+    MetricsColor = color.FgGreen
+    SchedulerColor = color.FgRed
 )
 
 
+// Transaction lock
+// Tasks run concurrently and share the node account, so transactions are sent one at a time to prevent nonce collisions
+var txLock sync.Mutex
+
+
 // Register node command
 func RegisterCommands(app *cli.App, name string, aliases []string) {
     app.Commands = append(app.Commands, cli.Command{
@@ -31,15 +55,65 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 // Run daemon
 func run(c *cli.Context) error {
 
+    // Configure
+    if err := services.ConfigureLogger(c); err != nil { return err }
+
     // Wait until node is registered
     if err := services.WaitNodeRegistered(c, true); err != nil { return err }
 
+    // Get services
+    cfg, err := services.GetConfig(c)
+    if err != nil { return err }
+    bc, err := services.GetBeaconClient(c)
+    if err != nil { return err }
+
     // Initialize tasks
-    stakePrelaunchMinipools, err := newStakePrelaunchMinipools(c, log.NewColorLogger(StakePrelaunchMinipoolsColor))
+    stakePrelaunchMinipools, err := newStakePrelaunchMinipools(c, log.NewLogger(StakePrelaunchMinipoolsColor).With("task", "stakePrelaunchMinipools"))
     if err != nil { return err }
 
-    // Start tasks
-    stakePrelaunchMinipools.Start()
+    // Initialize scheduler
+    s := scheduler.NewScheduler(log.NewLogger(SchedulerColor))
+    s.AddTask("stakePrelaunchMinipools", stakePrelaunchMinipools.run, stakePrelaunchMinipoolsSettings)
+    if cfg.Smartnode.AutoRefund {
+        refundMinipools, err := newRefundMinipools(c, log.NewLogger(RefundMinipoolsColor).With("task", "refundMinipools"))
+        if err != nil { return err }
+        s.AddTask("refundMinipools", refundMinipools.run, refundMinipoolsSettings)
+    }
+    if cfg.Smartnode.AutoWithdraw {
+        withdrawMinipools, err := newWithdrawMinipools(c, log.NewLogger(WithdrawMinipoolsColor).With("task", "withdrawMinipools"))
+        if err != nil { return err }
+        s.AddTask("withdrawMinipools", withdrawMinipools.run, withdrawMinipoolsSettings)
+    }
+    if cfg.Smartnode.AutoClose {
+        closeMinipools, err := newCloseMinipools(c, log.NewLogger(CloseMinipoolsColor).With("task", "closeMinipools"))
+        if err != nil { return err }
+        s.AddTask("closeMinipools", closeMinipools.run, closeMinipoolsSettings)
+    }
+    if cfg.Smartnode.MetricsAddress != "" {
+        s.AddTask("updateMetrics", func() error { return metrics.UpdateNodeMetrics(c) }, updateMetricsSettings)
+    }
+
+    // Check for prelaunch minipools on new blocks
+    eth1Watcher := events.NewEth1HeadWatcher(cfg.Chains.Eth1.WsProvider, log.NewLogger(EventsColor))
+    eth1Watcher.OnNewHead(func(header *types.Header) {
+        s.Trigger("stakePrelaunchMinipools")
+    })
+
+    // Update metrics when beacon finality changes
+    finalityWatcher := events.NewBeaconFinalityWatcher(bc, log.NewLogger(EventsColor))
+    if cfg.Smartnode.MetricsAddress != "" {
+        finalityWatcher.OnFinalityChange(func(head beacon.BeaconHead) {
+            s.Trigger("updateMetrics")
+        })
+    }
+
+    // Start tasks, event watchers & metrics server
+    s.Start()
+    eth1Watcher.Start()
+    if cfg.Smartnode.MetricsAddress != "" {
+        finalityWatcher.Start()
+    }
+    metrics.Start(cfg.Smartnode.MetricsAddress, log.NewLogger(MetricsColor))
 
     // Block thread
     select {}
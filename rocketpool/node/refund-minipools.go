@@ -0,0 +1,169 @@
+package node
+
+import (
+    "math/big"
+
+    "github.com/ethereum/go-ethereum/ethclient"
+    "github.com/rocket-pool/rocketpool-go/minipool"
+    "github.com/rocket-pool/rocketpool-go/rocketpool"
+    "github.com/rocket-pool/rocketpool-go/utils/eth"
+    "github.com/urfave/cli"
+    "golang.org/x/sync/errgroup"
+
+    "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/services/metrics"
+    "github.com/rocket-pool/smartnode/shared/services/wallet"
+    "github.com/rocket-pool/smartnode/shared/utils/log"
+    "github.com/rocket-pool/smartnode/shared/utils/math"
+)
+
+
+// Refund minipools task
+type refundMinipools struct {
+    c *cli.Context
+    log log.Logger
+    w *wallet.Wallet
+    ec *ethclient.Client
+    rp *rocketpool.RocketPool
+    maxGasPrice *big.Int
+    minRefund *big.Int
+}
+
+
+// Create refund minipools task
+func newRefundMinipools(c *cli.Context, logger log.Logger) (*refundMinipools, error) {
+
+    // Get services
+    cfg, err := services.GetConfig(c)
+    if err != nil { return nil, err }
+    w, err := services.GetWallet(c)
+    if err != nil { return nil, err }
+    ec, err := services.GetEthClient(c)
+    if err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+
+    // Get settings
+    maxGasPrice, err := cfg.GetAutoMaxGasPrice()
+    if err != nil { return nil, err }
+    minRefund, err := cfg.GetAutoMinRefund()
+    if err != nil { return nil, err }
+
+    // Return task
+    return &refundMinipools{
+        c: c,
+        log: logger,
+        w: w,
+        ec: ec,
+        rp: rp,
+        maxGasPrice: maxGasPrice,
+        minRefund: minRefund,
+    }, nil
+
+}
+
+
+// Refund minipools
+func (t *refundMinipools) run() error {
+
+    // Wait for eth client to sync
+    if err := services.WaitEthClientSynced(t.c, true); err != nil {
+        return err
+    }
+
+    // Log
+    t.log.Info("Checking for minipools to refund...")
+
+    // Get node account
+    nodeAccount, err := t.w.GetNodeAccount()
+    if err != nil {
+        return err
+    }
+
+    // Get node minipools
+    minipools, err := getNodeMinipools(t.rp, nodeAccount.Address)
+    if err != nil {
+        return err
+    }
+
+    // Data
+    var wg errgroup.Group
+    refundBalances := make([]*big.Int, len(minipools))
+
+    // Load minipool refund balances
+    for mi, mp := range minipools {
+        mi, mp := mi, mp
+        wg.Go(func() error {
+            refundBalance, err := mp.GetNodeRefundBalance(nil)
+            if err == nil { refundBalances[mi] = refundBalance }
+            return err
+        })
+    }
+
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return err
+    }
+
+    // Refund minipools with refund balances above the minimum
+    for mi, mp := range minipools {
+        refundBalance := refundBalances[mi]
+        if refundBalance.Cmp(big.NewInt(0)) == 0 {
+            continue
+        }
+        if refundBalance.Cmp(t.minRefund) < 0 {
+            t.log.Debug("Minipool refund balance is below the minimum refund amount", "minipool", mp.Address.Hex(), "refundBalanceEth", math.RoundDown(eth.WeiToEth(refundBalance), 6))
+            continue
+        }
+        if err := t.refundMinipool(mp, refundBalance); err != nil {
+            t.log.Error("Could not refund minipool", "minipool", mp.Address.Hex(), "error", err)
+        }
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Refund a minipool
+func (t *refundMinipools) refundMinipool(mp *minipool.Minipool, refundBalance *big.Int) error {
+
+    // Log
+    t.log.Info("Refunding minipool...", "minipool", mp.Address.Hex(), "refundBalanceEth", math.RoundDown(eth.WeiToEth(refundBalance), 6))
+
+    // Lock transactions
+    txLock.Lock()
+    defer txLock.Unlock()
+
+    // Get transactor
+    opts, err := t.w.GetNodeAccountTransactor()
+    if err != nil {
+        return err
+    }
+
+    // Check gas price
+    gasPrice, ok, err := checkAutoGasPrice(t.ec, opts, t.maxGasPrice)
+    if err != nil {
+        return err
+    }
+    if !ok {
+        t.log.Info("Gas price is above the maximum for automatic transactions; minipool will not be refunded yet.", "minipool", mp.Address.Hex(), "gasPriceGwei", eth.WeiToGwei(gasPrice), "maxGasPriceGwei", eth.WeiToGwei(t.maxGasPrice))
+        return nil
+    }
+
+    // Refund
+    txReceipt, err := mp.Refund(opts)
+    metrics.RecordTransaction("refundMinipools", err)
+    if err != nil {
+        return err
+    }
+
+    // Log
+    t.log.Info("Successfully refunded minipool.", "minipool", mp.Address.Hex(), "txHash", txReceipt.TxHash.Hex())
+
+    // Return
+    return nil
+
+}
+
@@ -1,26 +1,31 @@
 package node
 
 import (
+    "bytes"
     "context"
+    "encoding/hex"
     "errors"
     "fmt"
     "os"
-    "os/exec"
     "time"
 
-    "github.com/docker/docker/api/types"
-    "github.com/docker/docker/client"
     "github.com/ethereum/go-ethereum/common"
+    "github.com/prysmaticlabs/go-ssz"
     "github.com/rocket-pool/rocketpool-go/minipool"
     "github.com/rocket-pool/rocketpool-go/network"
     "github.com/rocket-pool/rocketpool-go/rocketpool"
     rptypes "github.com/rocket-pool/rocketpool-go/types"
     "github.com/urfave/cli"
+    eth2types "github.com/wealdtech/go-eth2-types/v2"
     "golang.org/x/sync/errgroup"
 
     "github.com/rocket-pool/smartnode/shared/services"
     "github.com/rocket-pool/smartnode/shared/services/beacon"
     "github.com/rocket-pool/smartnode/shared/services/config"
+    "github.com/rocket-pool/smartnode/shared/services/database"
+    "github.com/rocket-pool/smartnode/shared/services/keymanager"
+    "github.com/rocket-pool/smartnode/shared/services/metrics"
+    "github.com/rocket-pool/smartnode/shared/services/passwords"
     "github.com/rocket-pool/smartnode/shared/services/wallet"
     "github.com/rocket-pool/smartnode/shared/utils/log"
     "github.com/rocket-pool/smartnode/shared/utils/validator"
@@ -28,66 +33,88 @@ import (
 
 
 // Settings
-const ValidatorContainerSuffix = "_validator"
-const BeaconContainerSuffix = "_eth2"
-var stakePrelaunchMinipoolsInterval, _ = time.ParseDuration("5m")
-var validatorRestartTimeout, _ = time.ParseDuration("5s")
+const MinValidatorRestartInterval = 30 * time.Minute
+
+
+// Pre-stake safety checks
+const (
+    StakeCheckKeystore = "keystore"
+    StakeCheckValidatorExists = "validatorExists"
+    StakeCheckGenesisForkVersion = "genesisForkVersion"
+    StakeCheckDepositSignature = "depositSignature"
+    StakeCheckWithdrawalCredentials = "withdrawalCredentials"
+    StakeCheckDepositDataRoot = "depositDataRoot"
+)
 
 
 // Stake prelaunch minipools task
 type stakePrelaunchMinipools struct {
     c *cli.Context
-    log log.ColorLogger
+    log log.Logger
     cfg config.RocketPoolConfig
     w *wallet.Wallet
+    pm *passwords.PasswordManager
     rp *rocketpool.RocketPool
     bc beacon.Client
-    d *client.Client
+    vr *validatorRestarter
+    km *keymanager.Client
+    db *database.Database
+    restartPending bool
+    lastRestart time.Time
+    pendingKeysRecovered bool
 }
 
 
 // Create stake prelaunch minipools task
-func newStakePrelaunchMinipools(c *cli.Context, logger log.ColorLogger) (*stakePrelaunchMinipools, error) {
+func newStakePrelaunchMinipools(c *cli.Context, logger log.Logger) (*stakePrelaunchMinipools, error) {
 
     // Get services
     cfg, err := services.GetConfig(c)
     if err != nil { return nil, err }
     w, err := services.GetWallet(c)
     if err != nil { return nil, err }
+    pm, err := services.GetPasswordManager(c)
+    if err != nil { return nil, err }
     rp, err := services.GetRocketPool(c)
     if err != nil { return nil, err }
     bc, err := services.GetBeaconClient(c)
     if err != nil { return nil, err }
-    d, err := services.GetDocker(c)
+    db, err := services.GetDatabase(c)
+    if err != nil { return nil, err }
+
+    // Restore validator restart state; restarts pending when the daemon stopped are still required
+    restart, err := db.GetValidatorRestart()
     if err != nil { return nil, err }
 
+    // Get validator restarter
+    vr, err := newValidatorRestarter(c, logger)
+    if err != nil { return nil, err }
+
+    // Get validator key manager API client
+    var km *keymanager.Client
+    if cfg.Smartnode.KeymanagerUrl != "" {
+        km = keymanager.NewClient(cfg.Smartnode.KeymanagerUrl, os.ExpandEnv(cfg.Smartnode.KeymanagerTokenPath))
+    }
+
     // Return task
     return &stakePrelaunchMinipools{
         c: c,
         log: logger,
         cfg: cfg,
         w: w,
+        pm: pm,
         rp: rp,
         bc: bc,
-        d: d,
+        vr: vr,
+        km: km,
+        db: db,
+        restartPending: restart.Pending,
+        lastRestart: restart.LastRestart,
     }, nil
 
 }
 
 
-// Start stake prelaunch minipools task
-func (t *stakePrelaunchMinipools) Start() {
-    go (func() {
-        for {
-            if err := t.run(); err != nil {
-                t.log.Println(err)
-            }
-            time.Sleep(stakePrelaunchMinipoolsInterval)
-        }
-    })()
-}
-
-
 // Stake prelaunch minipools
 func (t *stakePrelaunchMinipools) run() error {
 
@@ -97,7 +124,7 @@ func (t *stakePrelaunchMinipools) run() error {
     }
 
     // Log
-    t.log.Println("Checking for minipools to launch...")
+    t.log.Info("Checking for minipools to launch...")
 
     // Get node account
     nodeAccount, err := t.w.GetNodeAccount()
@@ -105,13 +132,21 @@ func (t *stakePrelaunchMinipools) run() error {
         return err
     }
 
+    // Recover validator keys left pending by a previous run
+    if !t.pendingKeysRecovered {
+        if err := t.recoverPendingValidatorKeys(nodeAccount.Address); err != nil {
+            return err
+        }
+        t.pendingKeysRecovered = true
+    }
+
     // Get prelaunch minipools
     minipools, err := t.getPrelaunchMinipools(nodeAccount.Address)
     if err != nil {
         return err
     }
     if len(minipools) == 0 {
-        return nil
+        return t.restartValidatorIfPending()
     }
 
     // Data
@@ -139,22 +174,26 @@ func (t *stakePrelaunchMinipools) run() error {
     }
 
     // Log
-    t.log.Printlnf("%d minipools are ready for staking...", len(minipools))
+    t.log.Info("Minipools are ready for staking...", "count", len(minipools))
 
     // Stake minipools
+    validatorKeys := []*eth2types.BLSPrivateKey{}
     for _, mp := range minipools {
-        if err := t.stakeMinipool(mp, withdrawalCredentials, eth2Config); err != nil {
-            t.log.Println(fmt.Errorf("Could not stake minipool %s: %w", mp.Address.Hex(), err))
+        validatorKey, err := t.stakeMinipool(mp, withdrawalCredentials, eth2Config)
+        if err != nil {
+            t.log.Error("Could not stake minipool", "minipool", mp.Address.Hex(), "error", err)
+            continue
         }
+        validatorKeys = append(validatorKeys, validatorKey)
     }
 
-    // Restart validator process
-    if err := t.restartValidator(); err != nil {
-        return err
+    // Load new validator keys
+    if len(validatorKeys) > 0 {
+        t.loadValidatorKeys(validatorKeys)
     }
 
-    // Return
-    return nil
+    // Restart validator process if required
+    return t.restartValidatorIfPending()
 
 }
 
@@ -212,46 +251,185 @@ func (t *stakePrelaunchMinipools) getPrelaunchMinipools(nodeAddress common.Addre
 
 
 // Stake a minipool
-func (t *stakePrelaunchMinipools) stakeMinipool(mp *minipool.Minipool, withdrawalCredentials common.Hash, eth2Config beacon.Eth2Config) error {
+func (t *stakePrelaunchMinipools) stakeMinipool(mp *minipool.Minipool, withdrawalCredentials common.Hash, eth2Config beacon.Eth2Config) (*eth2types.BLSPrivateKey, error) {
 
     // Log
-    t.log.Printlnf("Staking minipool %s...", mp.Address.Hex())
+    t.log.Info("Staking minipool...", "minipool", mp.Address.Hex())
 
     // Create new validator key
     validatorKey, err := t.w.CreateValidatorKey()
     if err != nil {
-        return err
+        return nil, err
     }
 
     // Get validator deposit data
     depositData, depositDataRoot, err := validator.GetDepositData(validatorKey, withdrawalCredentials, eth2Config)
     if err != nil {
-        return err
+        t.clearPendingValidatorKey(validatorKey)
+        return nil, err
+    }
+
+    // Check validator key & deposit data
+    if check, err := t.checkStake(validatorKey, depositData, depositDataRoot, eth2Config); err != nil {
+        metrics.NodeStakeChecksFailed.WithLabelValues(check).Inc()
+        t.clearPendingValidatorKey(validatorKey)
+        return nil, fmt.Errorf("Pre-stake %s check failed, minipool will not be staked: %w", check, err)
     }
 
+    // Lock transactions
+    txLock.Lock()
+    defer txLock.Unlock()
+
     // Get transactor
     opts, err := t.w.GetNodeAccountTransactor()
     if err != nil {
-        return err
+        t.clearPendingValidatorKey(validatorKey)
+        return nil, err
+    }
+
+    // Get node account nonce before staking
+    nonce, err := t.rp.Client.PendingNonceAt(context.Background(), opts.From)
+    if err != nil {
+        t.clearPendingValidatorKey(validatorKey)
+        return nil, err
     }
 
     // Stake minipool
-    if _, err := mp.Stake(
-        rptypes.BytesToValidatorPubkey(depositData.PublicKey),
+    validatorPubkey := rptypes.BytesToValidatorPubkey(depositData.PublicKey)
+    txReceipt, err := mp.Stake(
+        validatorPubkey,
         rptypes.BytesToValidatorSignature(depositData.Signature),
         depositDataRoot,
         opts,
-    ); err != nil {
-        return err
+    )
+    metrics.RecordTransaction("stakePrelaunchMinipools", err)
+    if err != nil {
+        // No receipt is returned on failure; the key may still be used if the transaction was sent but not mined, so is only cleared
+        // if the transaction was never sent, or was mined without staking the minipool
+        if t.isStakeKeyUnused(mp, validatorPubkey, opts.From, nonce) {
+            t.clearPendingValidatorKey(validatorKey)
+        }
+        return nil, err
     }
 
-    // Save wallet
-    if err := t.w.Save(); err != nil {
+    // Clear pending validator key
+    t.clearPendingValidatorKey(validatorKey)
+
+    // Log
+    t.log.Info("Successfully staked minipool.", "minipool", mp.Address.Hex(), "validator", validatorPubkey.Hex(), "txHash", txReceipt.TxHash.Hex())
+
+    // Return
+    return validatorKey, nil
+
+}
+
+
+// Check whether a failed stake transaction has left its validator key unused
+// Transactions are locked, so an unchanged pending nonce means the transaction was never sent, and a changed confirmed nonce means it
+// was mined; keys are assumed to be in use if this cannot be determined
+func (t *stakePrelaunchMinipools) isStakeKeyUnused(mp *minipool.Minipool, validatorPubkey rptypes.ValidatorPubkey, from common.Address, nonce uint64) bool {
+
+    // Check if the transaction was sent
+    pendingNonce, err := t.rp.Client.PendingNonceAt(context.Background(), from)
+    if err != nil {
+        t.log.Warn("Could not check stake transaction nonce", "minipool", mp.Address.Hex(), "error", err)
+        return false
+    }
+    if pendingNonce == nonce {
+        return true
+    }
+
+    // Check if the transaction was mined
+    confirmedNonce, err := t.rp.Client.NonceAt(context.Background(), from, nil)
+    if err != nil {
+        t.log.Warn("Could not check stake transaction nonce", "minipool", mp.Address.Hex(), "error", err)
+        return false
+    }
+    if confirmedNonce <= nonce {
+        return false
+    }
+
+    // Check if the validator key was used by the minipool
+    minipoolAddress, err := minipool.GetMinipoolByPubkey(t.rp, validatorPubkey, nil)
+    if err != nil {
+        t.log.Warn("Could not check stake transaction validator", "minipool", mp.Address.Hex(), "error", err)
+        return false
+    }
+    return minipoolAddress != mp.Address
+
+}
+
+
+// Clear a pending validator key from the wallet
+// Failures are logged rather than returned; uncleared keys are resolved by recovery on the next daemon start
+func (t *stakePrelaunchMinipools) clearPendingValidatorKey(validatorKey *eth2types.BLSPrivateKey) {
+    if err := t.w.ClearPendingValidatorKey(validatorKey); err != nil {
+        t.log.Warn("Could not clear pending validator key", "validator", rptypes.BytesToValidatorPubkey(validatorKey.PublicKey().Marshal()).Hex(), "error", err)
+    }
+}
+
+
+// Recover validator keys left pending by a previous run
+// Pending keys used by a node minipool are restored to the keystores and loaded into the validator client; unused keys are discarded
+func (t *stakePrelaunchMinipools) recoverPendingValidatorKeys(nodeAddress common.Address) error {
+
+    // Get pending validator keys
+    pendingKeys, err := t.w.GetPendingValidatorKeys()
+    if err != nil {
         return err
     }
+    if len(pendingKeys) == 0 {
+        return nil
+    }
 
     // Log
-    t.log.Printlnf("Successfully staked minipool %s.", mp.Address.Hex())
+    t.log.Warn("Found pending validator keys from a previous run, recovering...", "count", len(pendingKeys))
+
+    // Get node minipool validator pubkeys
+    addresses, err := minipool.GetNodeMinipoolAddresses(t.rp, nodeAddress, nil)
+    if err != nil {
+        return err
+    }
+    pubkeys := make([]rptypes.ValidatorPubkey, len(addresses))
+    var wg errgroup.Group
+    for mi, address := range addresses {
+        mi, address := mi, address
+        wg.Go(func() error {
+            pubkey, err := minipool.GetMinipoolPubkey(t.rp, address, nil)
+            if err == nil { pubkeys[mi] = pubkey }
+            return err
+        })
+    }
+    if err := wg.Wait(); err != nil {
+        return err
+    }
+    minipoolPubkeys := make(map[rptypes.ValidatorPubkey]common.Address)
+    for mi, pubkey := range pubkeys {
+        minipoolPubkeys[pubkey] = addresses[mi]
+    }
+
+    // Resolve pending validator keys
+    recoveredKeys := []*eth2types.BLSPrivateKey{}
+    for _, validatorKey := range pendingKeys {
+        pubkey := rptypes.BytesToValidatorPubkey(validatorKey.PublicKey().Marshal())
+        if minipoolAddress, ok := minipoolPubkeys[pubkey]; ok {
+            if err := t.w.RecoverValidatorKey(pubkey); err != nil {
+                return fmt.Errorf("Could not recover validator %s key: %w", pubkey.Hex(), err)
+            }
+            recoveredKeys = append(recoveredKeys, validatorKey)
+            t.log.Info("Recovered pending validator key.", "validator", pubkey.Hex(), "minipool", minipoolAddress.Hex())
+        } else {
+            t.log.Warn("Pending validator key is not used by any node minipool; discarding.", "validator", pubkey.Hex())
+        }
+        if err := t.w.ClearPendingValidatorKey(validatorKey); err != nil {
+            return err
+        }
+    }
+
+    // Load recovered validator keys
+    if len(recoveredKeys) > 0 {
+        t.loadValidatorKeys(recoveredKeys)
+    }
 
     // Return
     return nil
@@ -259,106 +437,211 @@ func (t *stakePrelaunchMinipools) stakeMinipool(mp *minipool.Minipool, withdrawa
 }
 
 
-// Restart validator process
-func (t *stakePrelaunchMinipools) restartValidator() error {
+// Run pre-stake safety checks on a new validator key & its deposit data
+// Deposit data is checked against values re-read from the beacon node and network, rather than the inputs it was built from
+// Returns the failed check & an error if the minipool should not be staked
+func (t *stakePrelaunchMinipools) checkStake(validatorKey *eth2types.BLSPrivateKey, depositData validator.DepositData, depositDataRoot common.Hash, eth2Config beacon.Eth2Config) (string, error) {
 
-    // Restart validator container
-    if isInsideContainer() {
+    // Get validator pubkey
+    pubkey := rptypes.BytesToValidatorPubkey(depositData.PublicKey)
 
-        // Get validator container name & client type label
-        var containerName string
-        var clientTypeLabel string
-        if t.cfg.Smartnode.ProjectName == "" {
-            return errors.New("Rocket Pool docker project name not set")
-        }
-        switch clientType := t.bc.GetClientType(); clientType {
-            case beacon.SplitProcess:
-                containerName = t.cfg.Smartnode.ProjectName + ValidatorContainerSuffix
-                clientTypeLabel = "validator"
-            case beacon.SingleProcess:
-                containerName = t.cfg.Smartnode.ProjectName + BeaconContainerSuffix
-                clientTypeLabel = "beacon"
-            default:
-                return fmt.Errorf("Can't restart the validator, unknown client type '%d'", clientType)
-        }
+    // Check validator key was stored in all keystores so the validator client can load it
+    if err := t.w.VerifyValidatorKey(validatorKey); err != nil {
+        return StakeCheckKeystore, err
+    }
 
-        // Log
-        t.log.Printlnf("Restarting %s container (%s)...", clientTypeLabel, containerName)
+    // Check validator does not already exist on the beacon chain
+    status, err := t.bc.GetValidatorStatus(pubkey, nil)
+    if err != nil {
+        return StakeCheckValidatorExists, fmt.Errorf("Could not get validator %s status: %w", pubkey.Hex(), err)
+    }
+    if status.Exists {
+        return StakeCheckValidatorExists, fmt.Errorf("Validator %s already exists on the beacon chain", pubkey.Hex())
+    }
 
-        // Get all containers
-        containers, err := t.d.ContainerList(context.Background(), types.ContainerListOptions{All: true})
-        if err != nil {
-            return fmt.Errorf("Could not get docker containers: %w", err)
-        }
+    // Check the deposit data was signed with the beacon node's genesis fork version
+    genesisForkVersion, err := t.bc.GetGenesisForkVersion()
+    if err != nil {
+        return StakeCheckGenesisForkVersion, fmt.Errorf("Could not get genesis fork version: %w", err)
+    }
+    if !bytes.Equal(eth2Config.GenesisForkVersion, genesisForkVersion) {
+        return StakeCheckGenesisForkVersion, fmt.Errorf("Deposit data genesis fork version %s does not match beacon node genesis fork version %s", hex.EncodeToString(eth2Config.GenesisForkVersion), hex.EncodeToString(genesisForkVersion))
+    }
+
+    // Check deposit data signature
+    if err := validator.VerifyDepositData(depositData, eth2Config); err != nil {
+        return StakeCheckDepositSignature, err
+    }
+
+    // Check deposit data withdrawal credentials
+    withdrawalCredentials, err := network.GetWithdrawalCredentials(t.rp, nil)
+    if err != nil {
+        return StakeCheckWithdrawalCredentials, fmt.Errorf("Could not get network withdrawal credentials: %w", err)
+    }
+    if withdrawalCredentials == (common.Hash{}) {
+        return StakeCheckWithdrawalCredentials, errors.New("Network withdrawal credentials are not set")
+    }
+    if !bytes.Equal(depositData.WithdrawalCredentials, withdrawalCredentials.Bytes()) {
+        return StakeCheckWithdrawalCredentials, fmt.Errorf("Deposit data withdrawal credentials %s do not match network withdrawal credentials %s", hex.EncodeToString(depositData.WithdrawalCredentials), withdrawalCredentials.Hex())
+    }
+
+    // Check deposit data root
+    root, err := ssz.HashTreeRoot(depositData)
+    if err != nil {
+        return StakeCheckDepositDataRoot, fmt.Errorf("Could not get deposit data root: %w", err)
+    }
+    if common.Hash(root) != depositDataRoot {
+        return StakeCheckDepositDataRoot, fmt.Errorf("Deposit data root %s does not match deposit data", depositDataRoot.Hex())
+    }
+
+    // Return
+    return "", nil
+
+}
+
+
+// Load new validator keys into the validator client
+// Keys are hot-loaded where the client supports it; otherwise a validator restart is scheduled
+func (t *stakePrelaunchMinipools) loadValidatorKeys(validatorKeys []*eth2types.BLSPrivateKey) {
 
-        // Get validator container ID
-        var validatorContainerId string
-        for _, container := range containers {
-            if container.Names[0] == "/" + containerName {
-                validatorContainerId = container.ID
-                break
+    // Register keys held by the remote signer with the validator client via the key manager API
+    if t.cfg.Smartnode.RemoteSigner.Url != "" {
+        if t.km != nil {
+            if err := t.importRemoteValidatorKeys(validatorKeys); err != nil {
+                t.log.Warn("Could not register remote validator keys via the key manager API; the validator will be restarted instead.", "error", err)
+            } else {
+                t.log.Info("Successfully registered remote validator keys via the key manager API.", "count", len(validatorKeys))
+                return
             }
         }
-        if validatorContainerId == "" {
-            return errors.New("Validator container not found")
-        }
+        t.scheduleValidatorRestart()
+        return
+    }
 
-        // Restart validator container
-        if err := t.d.ContainerRestart(context.Background(), validatorContainerId, &validatorRestartTimeout); err != nil {
-            return fmt.Errorf("Could not restart validator container: %w", err)
+    // Prysm validator clients reload keys automatically when the wallet account store changes
+    if t.cfg.Chains.Eth2.Client.Selected == "prysm" {
+        t.log.Info("Validator keys will be loaded automatically by the validator client.", "count", len(validatorKeys))
+        return
+    }
+
+    // Import keys via the validator key manager API
+    if t.km != nil {
+        if err := t.importValidatorKeys(validatorKeys); err != nil {
+            t.log.Warn("Could not load validator keys via the key manager API; the validator will be restarted instead.", "error", err)
+        } else {
+            t.log.Info("Successfully loaded validator keys via the key manager API.", "count", len(validatorKeys))
+            return
         }
+    }
 
-    // Restart external validator process
-    } else {
+    // Schedule validator restart
+    t.scheduleValidatorRestart()
 
-        // Get validator restart command
-        restartCommand := os.ExpandEnv(t.cfg.Smartnode.ValidatorRestartCommand)
+}
 
-        // Log
-        t.log.Printlnf("Restarting validator process with command '%s'...", restartCommand)
 
-        // Run validator restart command bound to os stdout/stderr
-        cmd := exec.Command(restartCommand)
-        cmd.Stdout = os.Stdout
-        cmd.Stderr = os.Stderr
-        if err := cmd.Run(); err != nil {
-            return fmt.Errorf("Could not restart validator process: %w", err)
-        }
+// Import validator keys via the validator key manager API
+func (t *stakePrelaunchMinipools) importValidatorKeys(validatorKeys []*eth2types.BLSPrivateKey) error {
+
+    // Get wallet password
+    password, err := t.pm.GetPassword()
+    if err != nil {
+        return fmt.Errorf("Could not get wallet password: %w", err)
+    }
+
+    // Import keys
+    statuses, err := t.km.ImportKeys(validatorKeys, password)
+    if err != nil {
+        return err
+    }
 
+    // Check import statuses; keys already loaded by the validator client are reported as duplicates
+    for ki, status := range statuses {
+        if status.Status != keymanager.StatusImported && status.Status != keymanager.StatusDuplicate {
+            pubkey := rptypes.BytesToValidatorPubkey(validatorKeys[ki].PublicKey().Marshal())
+            return fmt.Errorf("Validator key %s was not imported: %s %s", pubkey.Hex(), status.Status, status.Message)
+        }
     }
 
-    // Log & return
-    t.log.Println("Successfully restarted validator")
+    // Return
     return nil
 
 }
 
 
-// Check if path exists
-func pathExists(path string) bool {
+// Register validator keys held by the remote signer with the validator client via the key manager API
+func (t *stakePrelaunchMinipools) importRemoteValidatorKeys(validatorKeys []*eth2types.BLSPrivateKey) error {
+
+    // Get validator pubkeys
+    pubkeys := make([]rptypes.ValidatorPubkey, len(validatorKeys))
+    for ki, key := range validatorKeys {
+        pubkeys[ki] = rptypes.BytesToValidatorPubkey(key.PublicKey().Marshal())
+    }
+
+    // Import remote keys
+    statuses, err := t.km.ImportRemoteKeys(pubkeys, t.cfg.GetRemoteSignerValidatorUrl())
+    if err != nil {
+        return err
+    }
 
-    // Check for file info at path
-    if _, err := os.Stat(path); err == nil {
-        return true;
+    // Check import statuses; keys already registered with the validator client are reported as duplicates
+    for ki, status := range statuses {
+        if status.Status != keymanager.StatusImported && status.Status != keymanager.StatusDuplicate {
+            return fmt.Errorf("Remote validator key %s was not registered: %s %s", pubkeys[ki].Hex(), status.Status, status.Message)
+        }
     }
 
-    // Assume that the path does not exist; this may result in false negatives (e.g. due to permissions)
-    return false;
+    // Return
+    return nil
+
+}
+
 
+// Schedule a validator restart
+// The pending restart is saved so that it is not lost if the daemon stops before it is performed
+func (t *stakePrelaunchMinipools) scheduleValidatorRestart() {
+    t.restartPending = true
+    if err := t.saveValidatorRestart(); err != nil {
+        t.log.Warn("Could not save pending validator restart.", "error", err)
+    }
 }
 
 
-// Check whether process is running inside a container
-func isInsideContainer() bool {
-    containerMarkerPaths := []string {
-        "/.dockerenv", // Docker
-        "/run/.containerenv", // Podman
+// Restart the validator process if a restart is pending
+// Restarts are rate-limited; keys staked while a restart is pending are loaded by the same restart
+func (t *stakePrelaunchMinipools) restartValidatorIfPending() error {
+
+    // Check for pending restart
+    if !t.restartPending {
+        return nil
     }
-    for _, path := range containerMarkerPaths {
-        if pathExists(path) {
-            return true;
+
+    // Check restart interval
+    if !t.lastRestart.IsZero() {
+        if sinceRestart := time.Since(t.lastRestart); sinceRestart < MinValidatorRestartInterval {
+            t.log.Info("Validator restart is pending.", "restartIn", (MinValidatorRestartInterval - sinceRestart).Round(time.Second).String())
+            return nil
         }
     }
-    return false;
+
+    // Restart validator process
+    if err := t.vr.restart(); err != nil {
+        return err
+    }
+
+    // Update restart status & return
+    t.restartPending = false
+    t.lastRestart = time.Now()
+    return t.saveValidatorRestart()
+
+}
+
+
+// Save the validator restart state to the database
+func (t *stakePrelaunchMinipools) saveValidatorRestart() error {
+    return t.db.SaveValidatorRestart(database.ValidatorRestart{
+        Pending: t.restartPending,
+        LastRestart: t.lastRestart,
+    })
 }
 
@@ -0,0 +1,61 @@
+package node
+
+import (
+    "context"
+    "math/big"
+
+    "github.com/ethereum/go-ethereum/accounts/abi/bind"
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/ethclient"
+    "github.com/rocket-pool/rocketpool-go/minipool"
+    "github.com/rocket-pool/rocketpool-go/rocketpool"
+)
+
+
+// Get node minipool contracts
+func getNodeMinipools(rp *rocketpool.RocketPool, nodeAddress common.Address) ([]*minipool.Minipool, error) {
+
+    // Get node minipool addresses
+    addresses, err := minipool.GetNodeMinipoolAddresses(rp, nodeAddress, nil)
+    if err != nil {
+        return []*minipool.Minipool{}, err
+    }
+
+    // Create minipool contracts
+    minipools := make([]*minipool.Minipool, len(addresses))
+    for mi, address := range addresses {
+        mp, err := minipool.NewMinipool(rp, address)
+        if err != nil {
+            return []*minipool.Minipool{}, err
+        }
+        minipools[mi] = mp
+    }
+
+    // Return
+    return minipools, nil
+
+}
+
+
+// Check the gas price for an automatic transaction against the maximum gas price
+// The suggested gas price is used and fixed on the transactor if no gas price is set
+// Returns the gas price and whether it is within the maximum
+func checkAutoGasPrice(ec *ethclient.Client, opts *bind.TransactOpts, maxGasPrice *big.Int) (*big.Int, bool, error) {
+
+    // Get gas price
+    if opts.GasPrice == nil {
+        gasPrice, err := ec.SuggestGasPrice(context.Background())
+        if err != nil {
+            return nil, false, err
+        }
+        opts.GasPrice = gasPrice
+    }
+
+    // Check gas price
+    if maxGasPrice == nil {
+        return opts.GasPrice, true, nil
+    }
+    return opts.GasPrice, (opts.GasPrice.Cmp(maxGasPrice) <= 0), nil
+
+}
+
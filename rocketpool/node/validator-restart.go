@@ -0,0 +1,298 @@
+package node
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "os"
+    "os/exec"
+    "strings"
+    "time"
+
+    "github.com/docker/docker/api/types"
+    "github.com/docker/docker/client"
+    "github.com/urfave/cli"
+
+    "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/services/beacon"
+    "github.com/rocket-pool/smartnode/shared/services/config"
+    "github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+
+// Settings
+const ValidatorContainerSuffix = "_validator"
+const BeaconContainerSuffix = "_eth2"
+var validatorRestartTimeout, _ = time.ParseDuration("5s")
+
+
+// Validator process restarter
+type validatorRestarter struct {
+    log log.Logger
+    mode string
+    timeout time.Duration
+    d *client.Client
+    containerName string
+    command string
+    args []string
+    env []string
+    unit string
+}
+
+
+// Create validator process restarter
+// The configured restart mode is validated on creation so that misconfiguration is reported on daemon startup
+func newValidatorRestarter(c *cli.Context, logger log.Logger) (*validatorRestarter, error) {
+
+    // Get services
+    cfg, err := services.GetConfig(c)
+    if err != nil { return nil, err }
+
+    // Get restart mode & timeout
+    mode, err := cfg.GetValidatorRestartMode(isInsideContainer())
+    if err != nil { return nil, err }
+    timeout, err := cfg.GetValidatorRestartTimeout()
+    if err != nil { return nil, err }
+
+    // Create restarter
+    r := &validatorRestarter{
+        log: logger,
+        mode: mode,
+        timeout: timeout,
+    }
+
+    // Initialize & validate restart mode
+    switch mode {
+        case config.ValidatorRestartDocker:
+            err = r.initDocker(c, cfg)
+        case config.ValidatorRestartCommand:
+            err = r.initCommand(cfg)
+        case config.ValidatorRestartSystemdUnit:
+            err = r.initSystemdUnit(cfg)
+        case config.ValidatorRestartNone:
+            logger.Warn("Validator restarts are disabled; new validator keys which can't be loaded automatically must be loaded manually.")
+    }
+    if err != nil {
+        return nil, fmt.Errorf("Invalid '%s' validator restart configuration: %w", mode, err)
+    }
+
+    // Return
+    return r, nil
+
+}
+
+
+// Restart validator process
+func (r *validatorRestarter) restart() error {
+
+    // Get restart context
+    ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+    defer cancel()
+
+    // Restart validator
+    switch r.mode {
+
+        // Restart validator container
+        case config.ValidatorRestartDocker:
+
+            // Log
+            r.log.Info("Restarting validator container...", "container", r.containerName)
+
+            // Get validator container ID
+            containerId, err := r.getContainerId(ctx)
+            if err != nil {
+                return err
+            }
+
+            // Restart validator container
+            if err := r.d.ContainerRestart(ctx, containerId, &validatorRestartTimeout); err != nil {
+                return fmt.Errorf("Could not restart validator container: %w", err)
+            }
+
+        // Restart external validator process
+        case config.ValidatorRestartCommand:
+
+            // Log
+            r.log.Info("Restarting validator process...", "command", r.command, "args", strings.Join(r.args, " "))
+
+            // Run validator restart command bound to os stdout/stderr
+            cmd := exec.CommandContext(ctx, r.command, r.args...)
+            cmd.Env = append(os.Environ(), r.env...)
+            cmd.Stdout = os.Stdout
+            cmd.Stderr = os.Stderr
+            if err := cmd.Run(); err != nil {
+                return fmt.Errorf("Could not restart validator process: %w", err)
+            }
+
+        // Restart validator systemd unit
+        case config.ValidatorRestartSystemdUnit:
+
+            // Log
+            r.log.Info("Restarting validator systemd unit...", "unit", r.unit)
+
+            // Run systemctl bound to os stdout/stderr
+            cmd := exec.CommandContext(ctx, "systemctl", "restart", r.unit)
+            cmd.Stdout = os.Stdout
+            cmd.Stderr = os.Stderr
+            if err := cmd.Run(); err != nil {
+                return fmt.Errorf("Could not restart validator systemd unit: %w", err)
+            }
+
+        // Restarts disabled
+        case config.ValidatorRestartNone:
+            r.log.Warn("Validator restart required to load new validator keys, but validator restarts are disabled.")
+            return nil
+
+    }
+
+    // Log & return
+    r.log.Info("Successfully restarted validator")
+    return nil
+
+}
+
+
+// Initialize docker restart mode
+func (r *validatorRestarter) initDocker(c *cli.Context, cfg config.RocketPoolConfig) error {
+
+    // Get services
+    d, err := services.GetDocker(c)
+    if err != nil { return err }
+    bc, err := services.GetBeaconClient(c)
+    if err != nil { return err }
+    r.d = d
+
+    // Get validator container name; defaults to the validator process container for the client type
+    if cfg.Smartnode.ValidatorRestart.Container != "" {
+        r.containerName = cfg.Smartnode.ValidatorRestart.Container
+    } else {
+        if cfg.Smartnode.ProjectName == "" {
+            return errors.New("Rocket Pool docker project name not set")
+        }
+        switch clientType := bc.GetClientType(); clientType {
+            case beacon.SplitProcess:
+                r.containerName = cfg.Smartnode.ProjectName + ValidatorContainerSuffix
+            case beacon.SingleProcess:
+                r.containerName = cfg.Smartnode.ProjectName + BeaconContainerSuffix
+            default:
+                return fmt.Errorf("Unknown client type '%d'", clientType)
+        }
+    }
+
+    // Check validator container exists
+    ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+    defer cancel()
+    if _, err := r.getContainerId(ctx); err != nil {
+        return err
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Initialize command restart mode
+func (r *validatorRestarter) initCommand(cfg config.RocketPoolConfig) error {
+
+    // Get command; defaults to the legacy validator restart command
+    command := cfg.Smartnode.ValidatorRestart.Command
+    if command == "" {
+        command = cfg.Smartnode.ValidatorRestartCommand
+    }
+    r.command = os.ExpandEnv(command)
+    if r.command == "" {
+        return errors.New("Validator restart command not set")
+    }
+    if _, err := exec.LookPath(r.command); err != nil {
+        return fmt.Errorf("Validator restart command '%s' not found: %w", r.command, err)
+    }
+
+    // Get arguments
+    r.args = make([]string, len(cfg.Smartnode.ValidatorRestart.Args))
+    for ai, arg := range cfg.Smartnode.ValidatorRestart.Args {
+        r.args[ai] = os.ExpandEnv(arg)
+    }
+
+    // Get environment
+    for _, env := range cfg.Smartnode.ValidatorRestart.Env {
+        if !strings.Contains(env, "=") || strings.HasPrefix(env, "=") {
+            return fmt.Errorf("Invalid validator restart command environment variable '%s', expected NAME=value", env)
+        }
+        r.env = append(r.env, env)
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Initialize systemd unit restart mode
+func (r *validatorRestarter) initSystemdUnit(cfg config.RocketPoolConfig) error {
+
+    // Get unit
+    r.unit = cfg.Smartnode.ValidatorRestart.Unit
+    if r.unit == "" {
+        return errors.New("Validator systemd unit not set")
+    }
+
+    // Check systemctl is available
+    if _, err := exec.LookPath("systemctl"); err != nil {
+        return fmt.Errorf("systemctl not found: %w", err)
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Get the validator container ID
+func (r *validatorRestarter) getContainerId(ctx context.Context) (string, error) {
+
+    // Get all containers
+    containers, err := r.d.ContainerList(ctx, types.ContainerListOptions{All: true})
+    if err != nil {
+        return "", fmt.Errorf("Could not get docker containers: %w", err)
+    }
+
+    // Get validator container ID
+    for _, container := range containers {
+        if container.Names[0] == "/" + r.containerName {
+            return container.ID, nil
+        }
+    }
+    return "", fmt.Errorf("Validator container '%s' not found", r.containerName)
+
+}
+
+
+// Check if path exists
+func pathExists(path string) bool {
+
+    // Check for file info at path
+    if _, err := os.Stat(path); err == nil {
+        return true;
+    }
+
+    // Assume that the path does not exist; this may result in false negatives (e.g. due to permissions)
+    return false;
+
+}
+
+
+// Check whether process is running inside a container
+func isInsideContainer() bool {
+    containerMarkerPaths := []string {
+        "/.dockerenv", // Docker
+        "/run/.containerenv", // Podman
+    }
+    for _, path := range containerMarkerPaths {
+        if pathExists(path) {
+            return true;
+        }
+    }
+    return false;
+}
+
@@ -0,0 +1,207 @@
+package node
+
+import (
+    "context"
+    "math/big"
+
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/ethclient"
+    "github.com/rocket-pool/rocketpool-go/minipool"
+    "github.com/rocket-pool/rocketpool-go/rocketpool"
+    "github.com/rocket-pool/rocketpool-go/settings"
+    "github.com/rocket-pool/rocketpool-go/types"
+    "github.com/rocket-pool/rocketpool-go/utils/eth"
+    "github.com/urfave/cli"
+    "golang.org/x/sync/errgroup"
+
+    "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/services/metrics"
+    "github.com/rocket-pool/smartnode/shared/services/wallet"
+    "github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+
+// Withdraw minipools task
+type withdrawMinipools struct {
+    c *cli.Context
+    log log.Logger
+    w *wallet.Wallet
+    ec *ethclient.Client
+    rp *rocketpool.RocketPool
+    maxGasPrice *big.Int
+}
+
+
+// Create withdraw minipools task
+func newWithdrawMinipools(c *cli.Context, logger log.Logger) (*withdrawMinipools, error) {
+
+    // Get services
+    cfg, err := services.GetConfig(c)
+    if err != nil { return nil, err }
+    w, err := services.GetWallet(c)
+    if err != nil { return nil, err }
+    ec, err := services.GetEthClient(c)
+    if err != nil { return nil, err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return nil, err }
+
+    // Get settings
+    maxGasPrice, err := cfg.GetAutoMaxGasPrice()
+    if err != nil { return nil, err }
+
+    // Return task
+    return &withdrawMinipools{
+        c: c,
+        log: logger,
+        w: w,
+        ec: ec,
+        rp: rp,
+        maxGasPrice: maxGasPrice,
+    }, nil
+
+}
+
+
+// Withdraw minipools
+func (t *withdrawMinipools) run() error {
+
+    // Wait for eth client to sync
+    if err := services.WaitEthClientSynced(t.c, true); err != nil {
+        return err
+    }
+
+    // Log
+    t.log.Info("Checking for minipools to withdraw...")
+
+    // Get node account
+    nodeAccount, err := t.w.GetNodeAccount()
+    if err != nil {
+        return err
+    }
+
+    // Get withdrawable minipools
+    minipools, err := t.getWithdrawableMinipools(nodeAccount.Address)
+    if err != nil {
+        return err
+    }
+    if len(minipools) == 0 {
+        return nil
+    }
+
+    // Log
+    t.log.Info("Minipools are ready for withdrawal...", "count", len(minipools))
+
+    // Withdraw minipools
+    for _, mp := range minipools {
+        if err := t.withdrawMinipool(mp); err != nil {
+            t.log.Error("Could not withdraw minipool", "minipool", mp.Address.Hex(), "error", err)
+        }
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Get minipools which are withdrawable and past the withdrawal delay
+func (t *withdrawMinipools) getWithdrawableMinipools(nodeAddress common.Address) ([]*minipool.Minipool, error) {
+
+    // Get node minipools
+    minipools, err := getNodeMinipools(t.rp, nodeAddress)
+    if err != nil {
+        return []*minipool.Minipool{}, err
+    }
+
+    // Data
+    var wg errgroup.Group
+    var currentBlock uint64
+    var withdrawalDelay uint64
+    statuses := make([]minipool.StatusDetails, len(minipools))
+
+    // Get current block
+    wg.Go(func() error {
+        header, err := t.ec.HeaderByNumber(context.Background(), nil)
+        if err == nil {
+            currentBlock = header.Number.Uint64()
+        }
+        return err
+    })
+
+    // Get withdrawal delay
+    wg.Go(func() error {
+        var err error
+        withdrawalDelay, err = settings.GetMinipoolWithdrawalDelay(t.rp, nil)
+        return err
+    })
+
+    // Load minipool statuses
+    for mi, mp := range minipools {
+        mi, mp := mi, mp
+        wg.Go(func() error {
+            status, err := mp.GetStatusDetails(nil)
+            if err == nil { statuses[mi] = status }
+            return err
+        })
+    }
+
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return []*minipool.Minipool{}, err
+    }
+
+    // Filter minipools by status & withdrawal delay
+    withdrawableMinipools := []*minipool.Minipool{}
+    for mi, mp := range minipools {
+        if statuses[mi].Status == types.Withdrawable && (currentBlock - statuses[mi].StatusBlock) >= withdrawalDelay {
+            withdrawableMinipools = append(withdrawableMinipools, mp)
+        }
+    }
+
+    // Return
+    return withdrawableMinipools, nil
+
+}
+
+
+// Withdraw a minipool
+func (t *withdrawMinipools) withdrawMinipool(mp *minipool.Minipool) error {
+
+    // Log
+    t.log.Info("Withdrawing minipool...", "minipool", mp.Address.Hex())
+
+    // Lock transactions
+    txLock.Lock()
+    defer txLock.Unlock()
+
+    // Get transactor
+    opts, err := t.w.GetNodeAccountTransactor()
+    if err != nil {
+        return err
+    }
+
+    // Check gas price
+    gasPrice, ok, err := checkAutoGasPrice(t.ec, opts, t.maxGasPrice)
+    if err != nil {
+        return err
+    }
+    if !ok {
+        t.log.Info("Gas price is above the maximum for automatic transactions; minipool will not be withdrawn yet.", "minipool", mp.Address.Hex(), "gasPriceGwei", eth.WeiToGwei(gasPrice), "maxGasPriceGwei", eth.WeiToGwei(t.maxGasPrice))
+        return nil
+    }
+
+    // Withdraw
+    txReceipt, err := mp.Withdraw(opts)
+    metrics.RecordTransaction("withdrawMinipools", err)
+    if err != nil {
+        return err
+    }
+
+    // Log
+    t.log.Info("Successfully withdrew minipool.", "minipool", mp.Address.Hex(), "txHash", txReceipt.TxHash.Hex())
+
+    // Return
+    return nil
+
+}
+
@@ -1,15 +1,16 @@
 package main
 
 import (
-    "fmt"
     "os"
 
+    "github.com/fatih/color"
     "github.com/urfave/cli"
 
     "github.com/rocket-pool/smartnode/rocketpool/api"
     "github.com/rocket-pool/smartnode/rocketpool/node"
     "github.com/rocket-pool/smartnode/rocketpool/watchtower"
     apiutils "github.com/rocket-pool/smartnode/shared/utils/api"
+    "github.com/rocket-pool/smartnode/shared/utils/log"
 )
 
 
@@ -63,6 +64,10 @@ func main() {
             Name:  "validatorKeychain, k",
             Usage: "Rocket Pool validator keychain absolute `path`",
         },
+        cli.StringFlag{
+            Name:  "database, d",
+            Usage: "Rocket Pool local database directory absolute `path`; defaults to a data directory alongside the global config",
+        },
         cli.StringFlag{
             Name:  "eth1Provider, e",
             Usage: "Eth 1.0 provider `address`",
@@ -71,6 +76,14 @@ func main() {
             Name:  "eth2Provider, b",
             Usage: "Eth 2.0 provider `address`",
         },
+        cli.StringFlag{
+            Name:  "eth1CheckProvider",
+            Usage: "Independent Eth 1.0 provider `address` used to verify network balances before submission",
+        },
+        cli.StringFlag{
+            Name:  "eth2CheckProvider",
+            Usage: "Independent Eth 2.0 provider `address` used to verify network balances before submission",
+        },
         cli.StringFlag{
             Name:  "gasPrice, g",
             Usage: "Desired gas price in gwei",
@@ -79,6 +92,18 @@ func main() {
             Name:  "gasLimit, l",
             Usage: "Desired gas limit",
         },
+        cli.StringFlag{
+            Name:  "metricsAddress, m",
+            Usage: "Daemon metrics HTTP server listen `address` (e.g. 0.0.0.0:9102); metrics are disabled if not set",
+        },
+        cli.StringFlag{
+            Name:  "logLevel",
+            Usage: "Minimum log `level` (debug, info, warn, error)",
+        },
+        cli.StringFlag{
+            Name:  "logFormat",
+            Usage: "Log output `format` (text, json)",
+        },
     }
 
     // Register commands
@@ -98,7 +123,7 @@ func main() {
         if commandName == "api" {
             apiutils.PrintErrorResponse(err)
         } else {
-            fmt.Fprintln(os.Stderr, err)
+            log.NewLogger(color.FgRed).Error("Command failed", "command", commandName, "error", err)
             os.Exit(1)
         }
     }
@@ -0,0 +1,170 @@
+package watchtower
+
+import (
+    "fmt"
+    "math/big"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/ethereum/go-ethereum/ethclient"
+    "github.com/rocket-pool/rocketpool-go/network"
+    "github.com/rocket-pool/rocketpool-go/rocketpool"
+    "github.com/rocket-pool/rocketpool-go/utils/eth"
+    "github.com/urfave/cli"
+    "golang.org/x/sync/errgroup"
+
+    "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/services/beacon"
+    "github.com/rocket-pool/smartnode/shared/services/database"
+    "github.com/rocket-pool/smartnode/shared/services/metrics"
+    "github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+
+// Network balances rejection reasons
+const (
+    BalancesCheckFailed = "checkFailed"
+    BalancesMismatch = "mismatch"
+    BalancesExchangeRateChange = "exchangeRateChange"
+)
+
+
+// Get the independent data sources used to check network balances
+// Providers without a configured check provider fall back to the primary provider; returns nil if no check providers are configured
+func getCheckBalancesSource(c *cli.Context, logger log.Logger, ec *ethclient.Client, rp *rocketpool.RocketPool, bc beacon.Client) (*balancesSource, error) {
+
+    // Get check services
+    checkEc, err := services.GetCheckEthClient(c)
+    if err != nil { return nil, err }
+    checkRp, err := services.GetCheckRocketPool(c)
+    if err != nil { return nil, err }
+    checkBc, err := services.GetCheckBeaconClient(c)
+    if err != nil { return nil, err }
+
+    // Check for configured check providers
+    if checkEc == nil && checkBc == nil {
+        logger.Warn("No Eth 1.0 or Eth 2.0 check provider configured; network balances will not be cross-checked before submission.")
+        return nil, nil
+    }
+
+    // Fall back to primary providers
+    if checkEc == nil {
+        checkEc = ec
+        checkRp = rp
+    }
+    if checkBc == nil {
+        checkBc = bc
+    }
+
+    // Return check source; the check source keeps its own minipool index in memory so that it is independent of the database
+    return &balancesSource{
+        ec: checkEc,
+        rp: checkRp,
+        bc: checkBc,
+        index: newMinipoolIndexer(logger.With("source", "check"), checkEc, checkRp, nil),
+    }, nil
+
+}
+
+
+// Check network balances before submission
+// Returns the rejection reason & an error if the balances should not be submitted
+func (t *submitNetworkBalances) checkBalances(balances networkBalances, totalEth *big.Int) (string, error) {
+
+    // Check balances against independent providers
+    if t.checkSource != nil {
+
+        // Log
+        t.log.Info("Checking network balances against independent providers...", "block", balances.Block)
+
+        // Get network balances at block
+        checkBalances, err := t.getNetworkBalances(*t.checkSource, balances.Block)
+        if err != nil {
+            return BalancesCheckFailed, fmt.Errorf("Could not calculate network balances from check providers: %w", err)
+        }
+
+        // Compare balances
+        mismatches := []string{}
+        compare := func(name string, value, checkValue *big.Int) {
+            if value.Cmp(checkValue) != 0 {
+                mismatches = append(mismatches, fmt.Sprintf("%s %s != %s", name, value.String(), checkValue.String()))
+            }
+        }
+        compare("depositPool", balances.DepositPool, checkBalances.DepositPool)
+        compare("minipoolsTotal", balances.MinipoolsTotal, checkBalances.MinipoolsTotal)
+        compare("minipoolsStaking", balances.MinipoolsStaking, checkBalances.MinipoolsStaking)
+        compare("rethContract", balances.RETHContract, checkBalances.RETHContract)
+        compare("rethSupply", balances.RETHSupply, checkBalances.RETHSupply)
+        if len(mismatches) > 0 {
+            return BalancesMismatch, fmt.Errorf("Network balances do not match check providers: %s", strings.Join(mismatches, ", "))
+        }
+
+    }
+
+    // Data
+    var wg errgroup.Group
+    var currentTotalEth *big.Int
+    var currentRethSupply *big.Int
+
+    // Get current network balances
+    wg.Go(func() error {
+        var err error
+        currentTotalEth, err = network.GetTotalETHBalance(t.rp, nil)
+        return err
+    })
+    wg.Go(func() error {
+        var err error
+        currentRethSupply, err = network.GetTotalRETHSupply(t.rp, nil)
+        return err
+    })
+
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return BalancesCheckFailed, fmt.Errorf("Could not get current network balances: %w", err)
+    }
+
+    // Check exchange rate change; skipped if either exchange rate is undefined
+    if currentRethSupply.Cmp(big.NewInt(0)) > 0 && currentTotalEth.Cmp(big.NewInt(0)) > 0 && balances.RETHSupply.Cmp(big.NewInt(0)) > 0 {
+        currentRate := eth.WeiToEth(currentTotalEth) / eth.WeiToEth(currentRethSupply)
+        newRate := eth.WeiToEth(totalEth) / eth.WeiToEth(balances.RETHSupply)
+        change := (newRate - currentRate) / currentRate
+        if change > t.maxExchangeRateChange || change < -t.maxExchangeRateChange {
+            return BalancesExchangeRateChange, fmt.Errorf("rETH exchange rate change from %.6f to %.6f (%.4f%%) exceeds the maximum of %.4f%%", currentRate, newRate, change * 100, t.maxExchangeRateChange * 100)
+        }
+    }
+
+    // Return
+    return "", nil
+
+}
+
+
+// Reject a network balances submission
+func (t *submitNetworkBalances) rejectBalances(balances networkBalances, totalEth *big.Int, reason string, err error) {
+
+    // Log & update metrics
+    t.log.Error("Network balances submission rejected", "block", balances.Block, "reason", reason, "error", err)
+    metrics.NetworkBalancesRejected.WithLabelValues(reason).Inc()
+
+    // Record rejection in journal
+    if t.dryRun {
+        return
+    }
+    if journalErr := t.db.AddSubmission(database.Submission{
+        Time: time.Now(),
+        Task: "submitNetworkBalances",
+        Method: "submitBalances",
+        Arguments: map[string]string{
+            "block": strconv.FormatUint(balances.Block, 10),
+            "totalEthWei": totalEth.String(),
+            "stakingEthWei": balances.MinipoolsStaking.String(),
+            "rethSupplyWei": balances.RETHSupply.String(),
+        },
+        Status: database.SubmissionRejected,
+        Error: err.Error(),
+    }); journalErr != nil {
+        t.log.Warn("Could not record rejected submission in database", "block", balances.Block, "error", journalErr)
+    }
+
+}
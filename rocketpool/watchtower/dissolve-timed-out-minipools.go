@@ -2,7 +2,6 @@ package watchtower
 
 import (
     "context"
-    "fmt"
 
     "github.com/ethereum/go-ethereum/common"
     "github.com/ethereum/go-ethereum/ethclient"
@@ -15,8 +14,11 @@ import (
     "golang.org/x/sync/errgroup"
 
     "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/services/database"
+    "github.com/rocket-pool/smartnode/shared/services/metrics"
     "github.com/rocket-pool/smartnode/shared/services/wallet"
     "github.com/rocket-pool/smartnode/shared/utils/log"
+    "github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
 
@@ -27,15 +29,17 @@ const MinipoolStatusBatchSize = 20
 // Dissolve timed out minipools task
 type dissolveTimedOutMinipools struct {
     c *cli.Context
-    log log.ColorLogger
+    log log.Logger
+    dryRun bool
     w *wallet.Wallet
     ec *ethclient.Client
     rp *rocketpool.RocketPool
+    db *database.Database
 }
 
 
 // Create dissolve timed out minipools task
-func newDissolveTimedOutMinipools(c *cli.Context, logger log.ColorLogger) (*dissolveTimedOutMinipools, error) {
+func newDissolveTimedOutMinipools(c *cli.Context, logger log.Logger) (*dissolveTimedOutMinipools, error) {
 
     // Get services
     w, err := services.GetWallet(c)
@@ -44,14 +48,18 @@ func newDissolveTimedOutMinipools(c *cli.Context, logger log.ColorLogger) (*diss
     if err != nil { return nil, err }
     rp, err := services.GetRocketPool(c)
     if err != nil { return nil, err }
+    db, err := services.GetDatabase(c)
+    if err != nil { return nil, err }
 
     // Return task
     return &dissolveTimedOutMinipools{
         c: c,
         log: logger,
+        dryRun: c.Bool("dry-run"),
         w: w,
         ec: ec,
         rp: rp,
+        db: db,
     }, nil
 
 }
@@ -81,7 +89,7 @@ func (t *dissolveTimedOutMinipools) run() error {
     }
 
     // Log
-    t.log.Println("Checking for timed out minipools to dissolve...")
+    t.log.Info("Checking for timed out minipools to dissolve...")
 
     // Get timed out minipools
     minipools, err := t.getTimedOutMinipools()
@@ -93,12 +101,12 @@ func (t *dissolveTimedOutMinipools) run() error {
     }
 
     // Log
-    t.log.Printlnf("%d minipools have timed out and will be dissolved...", len(minipools))
+    t.log.Info("Minipools have timed out and will be dissolved...", "count", len(minipools))
 
     // Dissolve minipools
     for _, mp := range minipools {
         if err := t.dissolveMinipool(mp); err != nil {
-            t.log.Println(fmt.Errorf("Could not dissolve minipool %s: %w", mp.Address.Hex(), err))
+            t.log.Error("Could not dissolve minipool", "minipool", mp.Address.Hex(), "error", err)
         }
     }
 
@@ -202,7 +210,16 @@ func (t *dissolveTimedOutMinipools) getTimedOutMinipools() ([]*minipool.Minipool
 func (t *dissolveTimedOutMinipools) dissolveMinipool(mp *minipool.Minipool) error {
 
     // Log
-    t.log.Printlnf("Dissolving minipool %s...", mp.Address.Hex())
+    t.log.Info("Dissolving minipool...", "minipool", mp.Address.Hex())
+
+    // Simulate dissolve in dry-run mode
+    if t.dryRun {
+        return t.simulateDissolveMinipool(mp)
+    }
+
+    // Lock transactions
+    txLock.Lock()
+    defer txLock.Unlock()
 
     // Get transactor
     opts, err := t.w.GetNodeAccountTransactor()
@@ -211,12 +228,41 @@ func (t *dissolveTimedOutMinipools) dissolveMinipool(mp *minipool.Minipool) erro
     }
 
     // Dissolve
-    if _, err := mp.Dissolve(opts); err != nil {
+    txReceipt, err := mp.Dissolve(opts)
+    metrics.RecordTransaction("dissolveTimedOutMinipools", err)
+    recordSubmission(t.db, t.log, "dissolveTimedOutMinipools", "dissolve", map[string]string{
+        "minipool": mp.Address.Hex(),
+    }, txReceipt, err)
+    if err != nil {
+        return err
+    }
+
+    // Log
+    t.log.Info("Successfully dissolved minipool.", "minipool", mp.Address.Hex(), "txHash", txReceipt.TxHash.Hex())
+
+    // Return
+    return nil
+
+}
+
+
+// Simulate dissolving a minipool without sending the transaction
+func (t *dissolveTimedOutMinipools) simulateDissolveMinipool(mp *minipool.Minipool) error {
+
+    // Get transactor
+    opts, err := t.w.GetNodeAccountTransactor()
+    if err != nil {
+        return err
+    }
+
+    // Simulate dissolve
+    gasEstimate, err := rp.SimulateTransaction(mp.Contract, opts, "dissolve")
+    if err != nil {
         return err
     }
 
     // Log
-    t.log.Printlnf("Successfully dissolved minipool %s.", mp.Address.Hex())
+    t.log.Info("Dry run: minipool would be dissolved; transaction not sent.", "minipool", mp.Address.Hex(), "gasEstimate", gasEstimate)
 
     // Return
     return nil
@@ -0,0 +1,371 @@
+package watchtower
+
+import (
+    "context"
+    "fmt"
+    "math/big"
+
+    "github.com/ethereum/go-ethereum"
+    "github.com/ethereum/go-ethereum/accounts/abi/bind"
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/ethclient"
+    "github.com/rocket-pool/rocketpool-go/minipool"
+    "github.com/rocket-pool/rocketpool-go/rocketpool"
+    "github.com/rocket-pool/rocketpool-go/types"
+    "golang.org/x/sync/errgroup"
+
+    "github.com/rocket-pool/smartnode/shared/services/database"
+    "github.com/rocket-pool/smartnode/shared/utils/log"
+    "github.com/rocket-pool/smartnode/shared/utils/rp"
+)
+
+
+// Settings
+const MinipoolIndexBatchSize = 20
+const MinipoolIndexLogBlockRange = 10000
+
+
+// Network minipool indexer
+// Minipool contract data only changes on minipool creation, destruction, status updates & deposits, so the index is updated incrementally
+// from minipool manager, minipool status & minipool deposit events, and only changed minipools are reloaded
+// Full deposit minipools may be assigned a user deposit without a status change, so deposit events are required to track user balances
+// The index is saved to the database if provided, and is otherwise held in memory
+type minipoolIndexer struct {
+    log log.Logger
+    ec *ethclient.Client
+    rp *rocketpool.RocketPool
+    db *database.Database
+    index database.MinipoolIndex
+}
+
+
+// Create network minipool indexer
+func newMinipoolIndexer(logger log.Logger, ec *ethclient.Client, rp *rocketpool.RocketPool, db *database.Database) *minipoolIndexer {
+    return &minipoolIndexer{
+        log: logger,
+        ec: ec,
+        rp: rp,
+        db: db,
+    }
+}
+
+
+// Get the network minipool index at a block
+func (m *minipoolIndexer) getIndex(blockNumber uint64) (database.MinipoolIndex, error) {
+
+    // Get block hash
+    header, err := m.ec.HeaderByNumber(context.Background(), big.NewInt(int64(blockNumber)))
+    if err != nil {
+        return database.MinipoolIndex{}, err
+    }
+    blockHash := header.Hash()
+
+    // Get saved index
+    index := m.index
+    if m.db != nil {
+        if index, err = m.db.GetMinipoolIndex(); err != nil {
+            m.log.Warn("Could not load minipool index, rebuilding...", "error", err)
+            index = database.MinipoolIndex{}
+        }
+    }
+
+    // Use saved index if current
+    if index.Block == blockNumber && index.BlockHash == blockHash && index.Minipools != nil {
+        return index, nil
+    }
+
+    // Update index incrementally if possible, or rebuild
+    updated := false
+    if index.Minipools != nil && index.Block > 0 && index.Block < blockNumber {
+        if err := m.updateIndex(&index, blockNumber); err != nil {
+            m.log.Warn("Could not update minipool index incrementally, rebuilding...", "fromBlock", index.Block, "toBlock", blockNumber, "error", err)
+        } else {
+            updated = true
+        }
+    }
+    if !updated {
+        if index, err = m.buildIndex(blockNumber); err != nil {
+            return database.MinipoolIndex{}, err
+        }
+    }
+
+    // Save index
+    index.Block = blockNumber
+    index.BlockHash = blockHash
+    m.index = index
+    if m.db != nil {
+        if err := m.db.SaveMinipoolIndex(index); err != nil {
+            m.log.Warn("Could not save minipool index", "block", blockNumber, "error", err)
+        }
+    }
+
+    // Return
+    return index, nil
+
+}
+
+
+// Build the network minipool index from all minipools at a block
+func (m *minipoolIndexer) buildIndex(blockNumber uint64) (database.MinipoolIndex, error) {
+
+    // Log
+    m.log.Info("Building network minipool index...", "block", blockNumber)
+
+    // Get minipool addresses
+    opts := &bind.CallOpts{BlockNumber: big.NewInt(int64(blockNumber))}
+    addresses, err := minipool.GetMinipoolAddresses(m.rp, opts)
+    if err != nil {
+        return database.MinipoolIndex{}, err
+    }
+
+    // Load minipools
+    minipools, err := m.loadMinipools(addresses, opts)
+    if err != nil {
+        return database.MinipoolIndex{}, err
+    }
+
+    // Return
+    return database.MinipoolIndex{
+        Block: blockNumber,
+        Minipools: minipools,
+    }, nil
+
+}
+
+
+// Update the network minipool index to a block from minipool events since the indexed block
+func (m *minipoolIndexer) updateIndex(index *database.MinipoolIndex, blockNumber uint64) error {
+
+    // Check indexed block has not been reorganised
+    indexedHeader, err := m.ec.HeaderByNumber(context.Background(), big.NewInt(int64(index.Block)))
+    if err != nil {
+        return err
+    }
+    if indexedHeader.Hash() != index.BlockHash {
+        return fmt.Errorf("Indexed block %d hash has changed", index.Block)
+    }
+
+    // Get contracts & event IDs
+    rocketMinipoolManager, err := m.rp.GetContract("rocketMinipoolManager")
+    if err != nil {
+        return err
+    }
+    rocketMinipoolAbi, err := m.rp.GetABI("rocketMinipool")
+    if err != nil {
+        return err
+    }
+    minipoolCreated, ok := rocketMinipoolManager.ABI.Events["MinipoolCreated"]
+    if !ok {
+        return fmt.Errorf("Event 'MinipoolCreated' does not exist on minipool manager contract")
+    }
+    minipoolDestroyed, ok := rocketMinipoolManager.ABI.Events["MinipoolDestroyed"]
+    if !ok {
+        return fmt.Errorf("Event 'MinipoolDestroyed' does not exist on minipool manager contract")
+    }
+    statusUpdated, ok := rocketMinipoolAbi.Events["StatusUpdated"]
+    if !ok {
+        return fmt.Errorf("Event 'StatusUpdated' does not exist on minipool contract")
+    }
+    etherDeposited, ok := rocketMinipoolAbi.Events["EtherDeposited"]
+    if !ok {
+        return fmt.Errorf("Event 'EtherDeposited' does not exist on minipool contract")
+    }
+
+    // Get changed & destroyed minipools from events in block ranges
+    changed := make(map[common.Address]bool)
+    destroyed := make(map[common.Address]bool)
+    for fromBlock := index.Block + 1; fromBlock <= blockNumber; fromBlock += MinipoolIndexLogBlockRange {
+        toBlock := fromBlock + MinipoolIndexLogBlockRange - 1
+        if toBlock > blockNumber { toBlock = blockNumber }
+
+        // Get minipool manager events
+        managerLogs, err := m.ec.FilterLogs(context.Background(), ethereum.FilterQuery{
+            FromBlock: big.NewInt(int64(fromBlock)),
+            ToBlock: big.NewInt(int64(toBlock)),
+            Addresses: []common.Address{*rocketMinipoolManager.Address},
+            Topics: [][]common.Hash{{minipoolCreated.ID, minipoolDestroyed.ID}},
+        })
+        if err != nil {
+            return fmt.Errorf("Could not get minipool manager events: %w", err)
+        }
+        for _, eventLog := range managerLogs {
+            if len(eventLog.Topics) < 2 {
+                return fmt.Errorf("Invalid minipool manager event in transaction %s", eventLog.TxHash.Hex())
+            }
+            address := common.BytesToAddress(eventLog.Topics[1].Bytes())
+            if eventLog.Topics[0] == minipoolCreated.ID {
+                changed[address] = true
+                delete(destroyed, address)
+            } else {
+                destroyed[address] = true
+                delete(changed, address)
+            }
+        }
+
+        // Get minipool status & deposit events; these are emitted by each minipool contract so are filtered by known minipools
+        minipoolLogs, err := m.ec.FilterLogs(context.Background(), ethereum.FilterQuery{
+            FromBlock: big.NewInt(int64(fromBlock)),
+            ToBlock: big.NewInt(int64(toBlock)),
+            Topics: [][]common.Hash{{statusUpdated.ID, etherDeposited.ID}},
+        })
+        if err != nil {
+            return fmt.Errorf("Could not get minipool status & deposit events: %w", err)
+        }
+        for _, eventLog := range minipoolLogs {
+            if _, ok := index.Minipools[eventLog.Address]; (ok || changed[eventLog.Address]) && !destroyed[eventLog.Address] {
+                changed[eventLog.Address] = true
+            }
+        }
+
+    }
+
+    // Withdrawal processing does not update minipool status, so unprocessed withdrawable minipools are always reloaded
+    // Minipools indexed before their user deposit was assigned are also reloaded, in case the index was saved without deposit events
+    for address, mp := range index.Minipools {
+        if destroyed[address] { continue }
+        if mp.Status == types.Withdrawable && !mp.WithdrawalProcessed {
+            changed[address] = true
+        }
+        if (mp.Status == types.Prelaunch || mp.Status == types.Staking) && mp.UserDepositTime == 0 {
+            changed[address] = true
+        }
+    }
+
+    // Load changed minipools
+    opts := &bind.CallOpts{BlockNumber: big.NewInt(int64(blockNumber))}
+    changedAddresses := make([]common.Address, 0, len(changed))
+    for address := range changed {
+        changedAddresses = append(changedAddresses, address)
+    }
+    changedMinipools, err := m.loadMinipools(changedAddresses, opts)
+    if err != nil {
+        return err
+    }
+
+    // Update index
+    for address := range destroyed {
+        delete(index.Minipools, address)
+    }
+    for address, mp := range changedMinipools {
+        index.Minipools[address] = mp
+    }
+
+    // Check index against network minipool count
+    minipoolCount, err := minipool.GetMinipoolCount(m.rp, opts)
+    if err != nil {
+        return err
+    }
+    if minipoolCount != uint64(len(index.Minipools)) {
+        return fmt.Errorf("Indexed minipool count %d does not match network minipool count %d", len(index.Minipools), minipoolCount)
+    }
+
+    // Log
+    m.log.Debug("Updated network minipool index", "fromBlock", index.Block, "toBlock", blockNumber, "changed", len(changedMinipools), "destroyed", len(destroyed))
+
+    // Return
+    return nil
+
+}
+
+
+// Load minipool data in batches
+func (m *minipoolIndexer) loadMinipools(addresses []common.Address, opts *bind.CallOpts) (map[common.Address]database.IndexedMinipool, error) {
+
+    // Load minipools in batches
+    minipools := make([]database.IndexedMinipool, len(addresses))
+    for bsi := 0; bsi < len(addresses); bsi += MinipoolIndexBatchSize {
+
+        // Get batch start & end index
+        msi := bsi
+        mei := bsi + MinipoolIndexBatchSize
+        if mei > len(addresses) { mei = len(addresses) }
+
+        // Load minipools
+        var wg errgroup.Group
+        for mi := msi; mi < mei; mi++ {
+            mi := mi
+            wg.Go(func() error {
+                mp, err := m.loadMinipool(addresses[mi], opts)
+                if err == nil { minipools[mi] = mp }
+                return err
+            })
+        }
+        if err := wg.Wait(); err != nil {
+            return map[common.Address]database.IndexedMinipool{}, err
+        }
+
+    }
+
+    // Save cached minipool deposits
+    if m.db != nil {
+        if err := m.db.SaveMinipools(); err != nil {
+            return map[common.Address]database.IndexedMinipool{}, err
+        }
+    }
+
+    // Build minipool map
+    minipoolMap := make(map[common.Address]database.IndexedMinipool)
+    for mi, address := range addresses {
+        minipoolMap[address] = minipools[mi]
+    }
+
+    // Return
+    return minipoolMap, nil
+
+}
+
+
+// Load a minipool's data
+func (m *minipoolIndexer) loadMinipool(minipoolAddress common.Address, opts *bind.CallOpts) (database.IndexedMinipool, error) {
+
+    // Create minipool
+    mp, err := minipool.NewMinipool(m.rp, minipoolAddress)
+    if err != nil {
+        return database.IndexedMinipool{}, err
+    }
+
+    // Data
+    var wg errgroup.Group
+    var status types.MinipoolStatus
+    var nodeFee float64
+    var withdrawalProcessed bool
+
+    // Load data
+    wg.Go(func() error {
+        var err error
+        status, err = mp.GetStatus(opts)
+        return err
+    })
+    wg.Go(func() error {
+        var err error
+        nodeFee, err = mp.GetNodeFee(opts)
+        return err
+    })
+    wg.Go(func() error {
+        var err error
+        withdrawalProcessed, err = minipool.GetMinipoolWithdrawalProcessed(m.rp, minipoolAddress, opts)
+        return err
+    })
+
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return database.IndexedMinipool{}, err
+    }
+
+    // Get deposits
+    deposits, err := rp.GetMinipoolDeposits(mp, m.db, status, opts)
+    if err != nil {
+        return database.IndexedMinipool{}, err
+    }
+
+    // Return
+    return database.IndexedMinipool{
+        Status: status,
+        NodeFee: nodeFee,
+        NodeDepositBalance: deposits.NodeDepositBalance,
+        UserDepositBalance: deposits.UserDepositBalance,
+        UserDepositTime: deposits.UserDepositTime,
+        WithdrawalProcessed: withdrawalProcessed,
+    }, nil
+
+}
@@ -1,39 +1,79 @@
 package watchtower
 
 import (
+    "math/big"
+
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/rocket-pool/rocketpool-go/minipool"
+    "github.com/rocket-pool/rocketpool-go/network"
+    "github.com/rocket-pool/rocketpool-go/node"
     "github.com/rocket-pool/rocketpool-go/rocketpool"
+    "github.com/rocket-pool/rocketpool-go/settings"
+    "github.com/rocket-pool/rocketpool-go/types"
+    "github.com/rocket-pool/rocketpool-go/utils/eth"
     "github.com/urfave/cli"
+    "golang.org/x/sync/errgroup"
 
     "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/services/beacon"
+    "github.com/rocket-pool/smartnode/shared/services/database"
+    "github.com/rocket-pool/smartnode/shared/services/metrics"
     "github.com/rocket-pool/smartnode/shared/services/wallet"
     "github.com/rocket-pool/smartnode/shared/utils/log"
+    "github.com/rocket-pool/smartnode/shared/utils/math"
+    "github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
 
+// Settings
+const MinipoolWithdrawalDetailsBatchSize = 20
+
+
 // Process withdrawals task
 type processWithdrawals struct {
     c *cli.Context
-    log log.ColorLogger
+    log log.Logger
+    dryRun bool
     w *wallet.Wallet
     rp *rocketpool.RocketPool
+    bc beacon.Client
+    db *database.Database
+}
+
+
+// Minipool withdrawal info
+type minipoolWithdrawalDetails struct {
+    Address common.Address
+    ValidatorPubkey types.ValidatorPubkey
+    TotalBalance *big.Int
+    NodeBalance *big.Int
+    FinalBalance *big.Int
+    Processable bool
 }
 
 
 // Create process withdrawals task
-func newProcessWithdrawals(c *cli.Context, logger log.ColorLogger) (*processWithdrawals, error) {
+func newProcessWithdrawals(c *cli.Context, logger log.Logger) (*processWithdrawals, error) {
 
     // Get services
     w, err := services.GetWallet(c)
     if err != nil { return nil, err }
     rp, err := services.GetRocketPool(c)
     if err != nil { return nil, err }
+    bc, err := services.GetBeaconClient(c)
+    if err != nil { return nil, err }
+    db, err := services.GetDatabase(c)
+    if err != nil { return nil, err }
 
     // Return task
     return &processWithdrawals{
         c: c,
         log: logger,
+        dryRun: c.Bool("dry-run"),
         w: w,
         rp: rp,
+        bc: bc,
+        db: db,
     }, nil
 
 }
@@ -42,11 +82,329 @@ func newProcessWithdrawals(c *cli.Context, logger log.ColorLogger) (*processWith
 // Process withdrawals
 func (t *processWithdrawals) run() error {
 
-    // Process withdrawals
-    // TODO: implement
+    // Wait for eth clients to sync
+    if err := services.WaitEthClientSynced(t.c, true); err != nil {
+        return err
+    }
+    if err := services.WaitBeaconClientSynced(t.c, true); err != nil {
+        return err
+    }
+
+    // Get node account
+    nodeAccount, err := t.w.GetNodeAccount()
+    if err != nil {
+        return err
+    }
+
+    // Data
+    var wg errgroup.Group
+    var nodeTrusted bool
+    var processWithdrawalsEnabled bool
+
+    // Get data
+    wg.Go(func() error {
+        var err error
+        nodeTrusted, err = node.GetNodeTrusted(t.rp, nodeAccount.Address, nil)
+        return err
+    })
+    wg.Go(func() error {
+        var err error
+        processWithdrawalsEnabled, err = settings.GetProcessWithdrawalsEnabled(t.rp, nil)
+        return err
+    })
+
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return err
+    }
+
+    // Check node trusted status & settings
+    if !(nodeTrusted && processWithdrawalsEnabled) {
+        return nil
+    }
+
+    // Log
+    t.log.Info("Checking for minipool withdrawals to process...")
+
+    // Get minipool withdrawal details
+    minipools, err := t.getNetworkMinipoolWithdrawalDetails()
+    if err != nil {
+        return err
+    }
+    if len(minipools) == 0 {
+        return nil
+    }
+
+    // Get withdrawal pool balance
+    withdrawalPoolBalance, err := network.GetWithdrawalBalance(t.rp, nil)
+    if err != nil {
+        return err
+    }
+
+    // Log
+    t.log.Info("Minipools have withdrawals to process...", "count", len(minipools))
+
+    // Process minipool withdrawals
+    for _, details := range minipools {
+
+        // Check withdrawal pool balance
+        if withdrawalPoolBalance.Cmp(details.TotalBalance) < 0 {
+            t.log.Warn("Insufficient withdrawal pool balance to process minipool withdrawal, skipping...", "minipool", details.Address.Hex(), "requiredEth", math.RoundDown(eth.WeiToEth(details.TotalBalance), 6))
+            continue
+        }
+
+        // Process withdrawal
+        if err := t.processWithdrawal(details); err != nil {
+            t.log.Error("Could not process minipool withdrawal", "minipool", details.Address.Hex(), "error", err)
+            continue
+        }
+
+        // Update remaining withdrawal pool balance
+        withdrawalPoolBalance.Sub(withdrawalPoolBalance, details.TotalBalance)
+
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Get all minipool withdrawal details
+func (t *processWithdrawals) getNetworkMinipoolWithdrawalDetails() ([]minipoolWithdrawalDetails, error) {
+
+    // Data
+    var wg1 errgroup.Group
+    var addresses []common.Address
+    var beaconHead beacon.BeaconHead
+
+    // Get minipool addresses
+    wg1.Go(func() error {
+        var err error
+        addresses, err = minipool.GetMinipoolAddresses(t.rp, nil)
+        return err
+    })
+
+    // Get beacon head
+    wg1.Go(func() error {
+        var err error
+        beaconHead, err = t.bc.GetBeaconHead()
+        return err
+    })
+
+    // Wait for data
+    if err := wg1.Wait(); err != nil {
+        return []minipoolWithdrawalDetails{}, err
+    }
+
+    // Get minipool validator statuses
+    validators, err := rp.GetMinipoolValidators(t.rp, t.bc, t.db, addresses, nil, nil)
+    if err != nil {
+        return []minipoolWithdrawalDetails{}, err
+    }
+
+    // Load details in batches
+    minipools := make([]minipoolWithdrawalDetails, len(addresses))
+    for bsi := 0; bsi < len(addresses); bsi += MinipoolWithdrawalDetailsBatchSize {
+
+        // Get batch start & end index
+        msi := bsi
+        mei := bsi + MinipoolWithdrawalDetailsBatchSize
+        if mei > len(addresses) { mei = len(addresses) }
+
+        // Log
+        t.log.Debug("Checking minipools for withdrawals to process...", "from", msi + 1, "to", mei, "total", len(addresses))
+
+        // Load details
+        var wg errgroup.Group
+        for mi := msi; mi < mei; mi++ {
+            mi := mi
+            wg.Go(func() error {
+                address := addresses[mi]
+                validator := validators[address]
+                mpDetails, err := t.getMinipoolWithdrawalDetails(address, validator, beaconHead)
+                if err == nil { minipools[mi] = mpDetails }
+                return err
+            })
+        }
+        if err := wg.Wait(); err != nil {
+            return []minipoolWithdrawalDetails{}, err
+        }
+
+    }
+
+    // Filter by processable status
+    processableMinipools := []minipoolWithdrawalDetails{}
+    for _, details := range minipools {
+        if details.Processable {
+            processableMinipools = append(processableMinipools, details)
+        }
+    }
+
+    // Return
+    return processableMinipools, nil
+
+}
+
+
+// Get minipool withdrawal details
+func (t *processWithdrawals) getMinipoolWithdrawalDetails(minipoolAddress common.Address, validator beacon.ValidatorStatus, beaconHead beacon.BeaconHead) (minipoolWithdrawalDetails, error) {
+
+    // Create minipool
+    mp, err := minipool.NewMinipool(t.rp, minipoolAddress)
+    if err != nil {
+        return minipoolWithdrawalDetails{}, err
+    }
+
+    // Data
+    var wg errgroup.Group
+    var status types.MinipoolStatus
+    var withdrawable bool
+    var withdrawalProcessed bool
+
+    // Load data
+    wg.Go(func() error {
+        var err error
+        status, err = mp.GetStatus(nil)
+        return err
+    })
+    wg.Go(func() error {
+        var err error
+        withdrawable, err = minipool.GetMinipoolWithdrawable(t.rp, minipoolAddress, nil)
+        return err
+    })
+    wg.Go(func() error {
+        var err error
+        withdrawalProcessed, err = minipool.GetMinipoolWithdrawalProcessed(t.rp, minipoolAddress, nil)
+        return err
+    })
+
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return minipoolWithdrawalDetails{}, err
+    }
+
+    // Check minipool status & withdrawal processed status
+    if status != types.Withdrawable || !withdrawable || withdrawalProcessed {
+        return minipoolWithdrawalDetails{}, nil
+    }
+
+    // Check validator has been fully withdrawn from the beacon chain
+    if !validator.Exists || validator.WithdrawableEpoch > beaconHead.FinalizedEpoch {
+        return minipoolWithdrawalDetails{}, nil
+    }
+
+    // Data
+    var wg2 errgroup.Group
+    var totalBalance *big.Int
+    var nodeBalance *big.Int
+
+    // Load withdrawal balances
+    wg2.Go(func() error {
+        var err error
+        totalBalance, err = minipool.GetMinipoolWithdrawalTotalBalance(t.rp, minipoolAddress, nil)
+        return err
+    })
+    wg2.Go(func() error {
+        var err error
+        nodeBalance, err = minipool.GetMinipoolWithdrawalNodeBalance(t.rp, minipoolAddress, nil)
+        return err
+    })
+
+    // Wait for data
+    if err := wg2.Wait(); err != nil {
+        return minipoolWithdrawalDetails{}, err
+    }
+
+    // Return
+    return minipoolWithdrawalDetails{
+        Address: minipoolAddress,
+        ValidatorPubkey: validator.Pubkey,
+        TotalBalance: totalBalance,
+        NodeBalance: nodeBalance,
+        FinalBalance: eth.GweiToWei(float64(validator.Balance)),
+        Processable: true,
+    }, nil
+
+}
+
+
+// Process a minipool withdrawal
+func (t *processWithdrawals) processWithdrawal(details minipoolWithdrawalDetails) error {
+
+    // Log
+    t.log.Info("Processing minipool withdrawal...",
+        "minipool", details.Address.Hex(),
+        "validator", details.ValidatorPubkey.Hex(),
+        "finalBalanceEth", math.RoundDown(eth.WeiToEth(details.FinalBalance), 6),
+        "totalWithdrawalBalanceEth", math.RoundDown(eth.WeiToEth(details.TotalBalance), 6),
+        "nodeWithdrawalBalanceEth", math.RoundDown(eth.WeiToEth(details.NodeBalance), 6))
+
+    // Simulate withdrawal processing in dry-run mode
+    if t.dryRun {
+        return t.simulateWithdrawal(details)
+    }
+
+    // Lock transactions
+    txLock.Lock()
+    defer txLock.Unlock()
+
+    // Get transactor
+    opts, err := t.w.GetNodeAccountTransactor()
+    if err != nil {
+        return err
+    }
+
+    // Process withdrawal
+    txReceipt, err := network.ProcessWithdrawal(t.rp, details.ValidatorPubkey, opts)
+    metrics.RecordTransaction("processWithdrawals", err)
+    recordSubmission(t.db, t.log, "processWithdrawals", "processWithdrawal", map[string]string{
+        "minipool": details.Address.Hex(),
+        "validator": details.ValidatorPubkey.Hex(),
+    }, txReceipt, err)
+    if err != nil {
+        return err
+    }
+
+    // Log
+    t.log.Info("Successfully processed minipool withdrawal.", "minipool", details.Address.Hex(), "txHash", txReceipt.TxHash.Hex())
 
     // Return
     return nil
 
 }
 
+
+// Simulate processing a minipool withdrawal without sending the transaction
+func (t *processWithdrawals) simulateWithdrawal(details minipoolWithdrawalDetails) error {
+
+    // Get transactor
+    opts, err := t.w.GetNodeAccountTransactor()
+    if err != nil {
+        return err
+    }
+
+    // Get contract
+    rocketNetworkWithdrawal, err := t.rp.GetContract("rocketNetworkWithdrawal")
+    if err != nil {
+        return err
+    }
+
+    // Simulate withdrawal processing
+    gasEstimate, err := rp.SimulateTransaction(rocketNetworkWithdrawal, opts, "processWithdrawal", details.ValidatorPubkey[:])
+    if err != nil {
+        return err
+    }
+
+    // Log
+    t.log.Info("Dry run: minipool withdrawal would be processed; transaction not sent.",
+        "minipool", details.Address.Hex(),
+        "validator", details.ValidatorPubkey.Hex(),
+        "expectedTotalWithdrawalWei", details.TotalBalance.String(),
+        "expectedNodeWithdrawalWei", details.NodeBalance.String(),
+        "gasEstimate", gasEstimate)
+
+    // Return
+    return nil
+
+}
@@ -4,6 +4,8 @@ import (
     "context"
     "fmt"
     "math/big"
+    "strconv"
+    "sync"
 
     "github.com/ethereum/go-ethereum/accounts/abi/bind"
     "github.com/ethereum/go-ethereum/common"
@@ -23,6 +25,8 @@ import (
 
     "github.com/rocket-pool/smartnode/shared/services"
     "github.com/rocket-pool/smartnode/shared/services/beacon"
+    "github.com/rocket-pool/smartnode/shared/services/database"
+    "github.com/rocket-pool/smartnode/shared/services/metrics"
     "github.com/rocket-pool/smartnode/shared/services/wallet"
     "github.com/rocket-pool/smartnode/shared/utils/eth2"
     "github.com/rocket-pool/smartnode/shared/utils/log"
@@ -38,11 +42,18 @@ const MinipoolBalanceDetailsBatchSize = 20
 // Submit network balances task
 type submitNetworkBalances struct {
     c *cli.Context
-    log log.ColorLogger
+    log log.Logger
+    dryRun bool
     w *wallet.Wallet
     ec *ethclient.Client
     rp *rocketpool.RocketPool
     bc beacon.Client
+    db *database.Database
+    source balancesSource
+    checkSource *balancesSource
+    maxExchangeRateChange float64
+    lastReportableBlock uint64
+    lastReportableBlockLock sync.Mutex
 }
 
 
@@ -61,8 +72,18 @@ type minipoolBalanceDetails struct {
 }
 
 
+// Network balance data sources
+type balancesSource struct {
+    ec *ethclient.Client
+    rp *rocketpool.RocketPool
+    bc beacon.Client
+    db *database.Database
+    index *minipoolIndexer
+}
+
+
 // Create submit network balances task
-func newSubmitNetworkBalances(c *cli.Context, logger log.ColorLogger) (*submitNetworkBalances, error) {
+func newSubmitNetworkBalances(c *cli.Context, logger log.Logger) (*submitNetworkBalances, error) {
 
     // Get services
     w, err := services.GetWallet(c)
@@ -73,15 +94,34 @@ func newSubmitNetworkBalances(c *cli.Context, logger log.ColorLogger) (*submitNe
     if err != nil { return nil, err }
     bc, err := services.GetBeaconClient(c)
     if err != nil { return nil, err }
+    db, err := services.GetDatabase(c)
+    if err != nil { return nil, err }
+    checkSource, err := getCheckBalancesSource(c, logger, ec, rp, bc)
+    if err != nil { return nil, err }
+    cfg, err := services.GetConfig(c)
+    if err != nil { return nil, err }
+    maxExchangeRateChange, err := cfg.GetMaxExchangeRateChange()
+    if err != nil { return nil, err }
 
     // Return task
     return &submitNetworkBalances{
         c: c,
         log: logger,
+        dryRun: c.Bool("dry-run"),
         w: w,
         ec: ec,
         rp: rp,
         bc: bc,
+        db: db,
+        source: balancesSource{
+            ec: ec,
+            rp: rp,
+            bc: bc,
+            db: db,
+            index: newMinipoolIndexer(logger, ec, rp, db),
+        },
+        checkSource: checkSource,
+        maxExchangeRateChange: maxExchangeRateChange,
     }, nil
 
 }
@@ -132,7 +172,7 @@ func (t *submitNetworkBalances) run() error {
     }
 
     // Log
-    t.log.Println("Checking for network balance checkpoint...")
+    t.log.Info("Checking for network balance checkpoint...")
 
     // Get block to submit balances for
     blockNumber, err := t.getLatestReportableBlock()
@@ -150,20 +190,22 @@ func (t *submitNetworkBalances) run() error {
     }
 
     // Log
-    t.log.Printlnf("Calculating network balances for block %d...", blockNumber)
+    t.log.Info("Calculating network balances...", "block", blockNumber)
 
     // Get network balances at block
-    balances, err := t.getNetworkBalances(blockNumber)
+    balances, err := t.getNetworkBalances(t.source, blockNumber)
     if err != nil {
         return err
     }
 
     // Log
-    t.log.Printlnf("Deposit pool balance: %.6f ETH", math.RoundDown(eth.WeiToEth(balances.DepositPool), 6))
-    t.log.Printlnf("Total minipool user balance: %.6f ETH", math.RoundDown(eth.WeiToEth(balances.MinipoolsTotal), 6))
-    t.log.Printlnf("Staking minipool user balance: %.6f ETH", math.RoundDown(eth.WeiToEth(balances.MinipoolsStaking), 6))
-    t.log.Printlnf("rETH contract balance: %.6f ETH", math.RoundDown(eth.WeiToEth(balances.RETHContract), 6))
-    t.log.Printlnf("rETH token supply: %.6f rETH", math.RoundDown(eth.WeiToEth(balances.RETHSupply), 6))
+    t.log.Info("Calculated network balances",
+        "block", balances.Block,
+        "depositPoolEth", math.RoundDown(eth.WeiToEth(balances.DepositPool), 6),
+        "minipoolsTotalEth", math.RoundDown(eth.WeiToEth(balances.MinipoolsTotal), 6),
+        "minipoolsStakingEth", math.RoundDown(eth.WeiToEth(balances.MinipoolsStaking), 6),
+        "rethContractEth", math.RoundDown(eth.WeiToEth(balances.RETHContract), 6),
+        "rethSupply", math.RoundDown(eth.WeiToEth(balances.RETHSupply), 6))
 
     // Submit balances
     if err := t.submitBalances(balances); err != nil {
@@ -211,6 +253,32 @@ func (t *submitNetworkBalances) getLatestReportableBlock() (uint64, error) {
 }
 
 
+// Check whether the latest reportable block has changed as of a new block
+func (t *submitNetworkBalances) reportableBlockChanged(currentBlock uint64) (bool, error) {
+
+    // Get balance submission frequency
+    submitBalancesFrequency, err := settings.GetSubmitBalancesFrequency(t.rp, nil)
+    if err != nil {
+        return false, err
+    }
+
+    // Get reportable block
+    reportableBlock := (currentBlock / submitBalancesFrequency) * submitBalancesFrequency
+
+    // Check & update last reportable block
+    t.lastReportableBlockLock.Lock()
+    defer t.lastReportableBlockLock.Unlock()
+    changed := (t.lastReportableBlock != 0 && reportableBlock > t.lastReportableBlock)
+    if reportableBlock > t.lastReportableBlock {
+        t.lastReportableBlock = reportableBlock
+    }
+
+    // Return
+    return changed, nil
+
+}
+
+
 // Check whether balances for a block can be submitted by the node
 func (t *submitNetworkBalances) canSubmitBlockBalances(nodeAddress common.Address, blockNumber uint64) (bool, error) {
 
@@ -245,7 +313,7 @@ func (t *submitNetworkBalances) canSubmitBlockBalances(nodeAddress common.Addres
 
 
 // Get the network balances at a specific block
-func (t *submitNetworkBalances) getNetworkBalances(blockNumber uint64) (networkBalances, error) {
+func (t *submitNetworkBalances) getNetworkBalances(src balancesSource, blockNumber uint64) (networkBalances, error) {
 
     // Initialize call options
     opts := &bind.CallOpts{
@@ -262,31 +330,31 @@ func (t *submitNetworkBalances) getNetworkBalances(blockNumber uint64) (networkB
     // Get deposit pool balance
     wg.Go(func() error {
         var err error
-        depositPoolBalance, err = deposit.GetBalance(t.rp, opts)
+        depositPoolBalance, err = deposit.GetBalance(src.rp, opts)
         return err
     })
 
     // Get minipool balance details
     wg.Go(func() error {
         var err error
-        minipoolBalanceDetails, err = t.getNetworkMinipoolBalanceDetails(opts)
+        minipoolBalanceDetails, err = t.getNetworkMinipoolBalanceDetails(src, opts)
         return err
     })
 
     // Get rETH contract balance
     wg.Go(func() error {
-        rethContractAddress, err := t.rp.GetAddress("rocketETHToken")
+        rethContractAddress, err := src.rp.GetAddress("rocketETHToken")
         if err != nil {
             return err
         }
-        rethContractBalance, err = t.ec.BalanceAt(context.Background(), *rethContractAddress, opts.BlockNumber)
+        rethContractBalance, err = src.ec.BalanceAt(context.Background(), *rethContractAddress, opts.BlockNumber)
         return err
     })
 
     // Get rETH token supply
     wg.Go(func() error {
         var err error
-        rethTotalSupply, err = tokens.GetRETHTotalSupply(t.rp, opts)
+        rethTotalSupply, err = tokens.GetRETHTotalSupply(src.rp, opts)
         return err
     })
 
@@ -320,39 +388,39 @@ func (t *submitNetworkBalances) getNetworkBalances(blockNumber uint64) (networkB
 
 
 // Get all minipool balance details
-func (t *submitNetworkBalances) getNetworkMinipoolBalanceDetails(opts *bind.CallOpts) ([]minipoolBalanceDetails, error) {
+func (t *submitNetworkBalances) getNetworkMinipoolBalanceDetails(src balancesSource, opts *bind.CallOpts) ([]minipoolBalanceDetails, error) {
 
     // Data
     var wg1 errgroup.Group
-    var addresses []common.Address
+    var index database.MinipoolIndex
     var eth2Config beacon.Eth2Config
     var beaconHead beacon.BeaconHead
     var blockTime uint64
 
-    // Get minipool addresses
+    // Get network minipool index
     wg1.Go(func() error {
         var err error
-        addresses, err = minipool.GetMinipoolAddresses(t.rp, opts)
+        index, err = src.index.getIndex(opts.BlockNumber.Uint64())
         return err
     })
 
     // Get eth2 config
     wg1.Go(func() error {
         var err error
-        eth2Config, err = t.bc.GetEth2Config()
+        eth2Config, err = src.bc.GetEth2Config()
         return err
     })
 
     // Get beacon head
     wg1.Go(func() error {
         var err error
-        beaconHead, err = t.bc.GetBeaconHead()
+        beaconHead, err = src.bc.GetBeaconHead()
         return err
     })
 
     // Get block time
     wg1.Go(func() error {
-        header, err := t.ec.HeaderByNumber(context.Background(), opts.BlockNumber)
+        header, err := src.ec.HeaderByNumber(context.Background(), opts.BlockNumber)
         if err == nil {
             blockTime = header.Time
         }
@@ -370,8 +438,18 @@ func (t *submitNetworkBalances) getNetworkMinipoolBalanceDetails(opts *bind.Call
         return []minipoolBalanceDetails{}, fmt.Errorf("Epoch %d at block %s is higher than current epoch %d", blockEpoch, opts.BlockNumber.String(), beaconHead.Epoch)
     }
 
+    // Get minipool addresses & addresses of minipools which may have validator balances
+    addresses := make([]common.Address, 0, len(index.Minipools))
+    validatorAddresses := []common.Address{}
+    for address, mp := range index.Minipools {
+        addresses = append(addresses, address)
+        if (mp.Status == types.Staking || mp.Status == types.Withdrawable) && mp.UserDepositBalance.Cmp(big.NewInt(0)) > 0 && !mp.WithdrawalProcessed {
+            validatorAddresses = append(validatorAddresses, address)
+        }
+    }
+
     // Get minipool validator statuses
-    validators, err := rp.GetMinipoolValidators(t.rp, t.bc, addresses, opts, &beacon.ValidatorStatusOptions{Epoch: blockEpoch})
+    validators, err := rp.GetMinipoolValidators(src.rp, src.bc, src.db, validatorAddresses, opts, &beacon.ValidatorStatusOptions{Epoch: blockEpoch})
     if err != nil {
         return []minipoolBalanceDetails{}, err
     }
@@ -385,9 +463,6 @@ func (t *submitNetworkBalances) getNetworkMinipoolBalanceDetails(opts *bind.Call
         mei := bsi + MinipoolBalanceDetailsBatchSize
         if mei > len(addresses) { mei = len(addresses) }
 
-        // Log
-        //t.log.Printlnf("Calculating balances for minipools %d - %d of %d...", msi + 1, mei, len(addresses))
-
         // Load details
         var wg errgroup.Group
         for mi := msi; mi < mei; mi++ {
@@ -395,7 +470,7 @@ func (t *submitNetworkBalances) getNetworkMinipoolBalanceDetails(opts *bind.Call
             wg.Go(func() error {
                 address := addresses[mi]
                 validator := validators[address]
-                mpDetails, err := t.getMinipoolBalanceDetails(address, opts, validator, eth2Config, blockEpoch)
+                mpDetails, err := t.getMinipoolBalanceDetails(src, index.Minipools[address], opts, validator, eth2Config, blockEpoch)
                 if err == nil { details[mi] = mpDetails }
                 return err
             })
@@ -413,85 +488,31 @@ func (t *submitNetworkBalances) getNetworkMinipoolBalanceDetails(opts *bind.Call
 
 
 // Get minipool balance details
-func (t *submitNetworkBalances) getMinipoolBalanceDetails(minipoolAddress common.Address, opts *bind.CallOpts, validator beacon.ValidatorStatus, eth2Config beacon.Eth2Config, blockEpoch uint64) (minipoolBalanceDetails, error) {
-
-    // Create minipool
-    mp, err := minipool.NewMinipool(t.rp, minipoolAddress)
-    if err != nil {
-        return minipoolBalanceDetails{}, err
-    }
-
-    // Data
-    var wg errgroup.Group
-    var status types.MinipoolStatus
-    var nodeFee float64
-    var nodeDepositBalance *big.Int
-    var userDepositBalance *big.Int
-    var userDepositTime uint64
-    var withdrawalProcessed bool
-
-    // Load data
-    wg.Go(func() error {
-        var err error
-        status, err = mp.GetStatus(opts)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        nodeFee, err = mp.GetNodeFee(opts)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        nodeDepositBalance, err = mp.GetNodeDepositBalance(opts)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        userDepositBalance, err = mp.GetUserDepositBalance(opts)
-        return err
-    })
-    wg.Go(func() error {
-        userDepositAssignedTime, err := mp.GetUserDepositAssignedTime(opts)
-        if err == nil {
-            userDepositTime = uint64(userDepositAssignedTime.Unix())
-        }
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        withdrawalProcessed, err = minipool.GetMinipoolWithdrawalProcessed(t.rp, minipoolAddress, opts)
-        return err
-    })
-
-    // Wait for data
-    if err := wg.Wait(); err != nil {
-        return minipoolBalanceDetails{}, err
-    }
+func (t *submitNetworkBalances) getMinipoolBalanceDetails(src balancesSource, mp database.IndexedMinipool, opts *bind.CallOpts, validator beacon.ValidatorStatus, eth2Config beacon.Eth2Config, blockEpoch uint64) (minipoolBalanceDetails, error) {
 
     // No balance if no user deposit assigned or withdrawal has been processed
-    if userDepositBalance.Cmp(big.NewInt(0)) == 0 || withdrawalProcessed {
+    if mp.UserDepositBalance.Cmp(big.NewInt(0)) == 0 || mp.WithdrawalProcessed {
         return minipoolBalanceDetails{
             UserBalance: big.NewInt(0),
         }, nil
     }
 
     // Use user deposit balance if initialized or prelaunch
-    if status == types.Initialized || status == types.Prelaunch {
+    if mp.Status == types.Initialized || mp.Status == types.Prelaunch {
         return minipoolBalanceDetails{
-            UserBalance: userDepositBalance,
+            UserBalance: mp.UserDepositBalance,
         }, nil
     }
 
     // Use user deposit balance if validator not yet active on beacon chain at block
     if !validator.Exists || validator.ActivationEpoch >= blockEpoch {
         return minipoolBalanceDetails{
-            UserBalance: userDepositBalance,
+            UserBalance: mp.UserDepositBalance,
         }, nil
     }
 
     // Get start epoch for node balance calculation
-    startEpoch := eth2.EpochAt(eth2Config, userDepositTime)
+    startEpoch := eth2.EpochAt(eth2Config, mp.UserDepositTime)
     if startEpoch < validator.ActivationEpoch {
         startEpoch = validator.ActivationEpoch
     } else if startEpoch > blockEpoch {
@@ -500,7 +521,7 @@ func (t *submitNetworkBalances) getMinipoolBalanceDetails(minipoolAddress common
 
     // Get validator activation balance
     activationBalanceWei := new(big.Int)
-    activationBalanceWei.Add(nodeDepositBalance, userDepositBalance)
+    activationBalanceWei.Add(mp.NodeDepositBalance, mp.UserDepositBalance)
     activationBalance := eth.WeiToGwei(activationBalanceWei)
 
     // Calculate approximate validator balance at start epoch & validator balance at block
@@ -508,7 +529,7 @@ func (t *submitNetworkBalances) getMinipoolBalanceDetails(minipoolAddress common
     blockBalance := eth.GweiToWei(float64(validator.Balance))
 
     // Get node & user balance at block
-    nodeBalance, err := minipool.GetMinipoolNodeRewardAmount(t.rp, nodeFee, userDepositBalance, startBalance, blockBalance, opts)
+    nodeBalance, err := minipool.GetMinipoolNodeRewardAmount(src.rp, mp.NodeFee, mp.UserDepositBalance, startBalance, blockBalance, opts)
     if err != nil {
         return minipoolBalanceDetails{}, err
     }
@@ -528,7 +549,7 @@ func (t *submitNetworkBalances) getMinipoolBalanceDetails(minipoolAddress common
 func (t *submitNetworkBalances) submitBalances(balances networkBalances) error {
 
     // Log
-    t.log.Printlnf("Submitting network balances for block %d...", balances.Block)
+    t.log.Info("Submitting network balances...", "block", balances.Block)
 
     // Calculate total ETH balance
     totalEth := big.NewInt(0)
@@ -536,6 +557,21 @@ func (t *submitNetworkBalances) submitBalances(balances networkBalances) error {
     totalEth.Add(totalEth, balances.MinipoolsTotal)
     totalEth.Add(totalEth, balances.RETHContract)
 
+    // Check balances
+    if reason, err := t.checkBalances(balances, totalEth); err != nil {
+        t.rejectBalances(balances, totalEth, reason, err)
+        return err
+    }
+
+    // Simulate submission in dry-run mode
+    if t.dryRun {
+        return t.simulateBalances(balances, totalEth)
+    }
+
+    // Lock transactions
+    txLock.Lock()
+    defer txLock.Unlock()
+
     // Get transactor
     opts, err := t.w.GetNodeAccountTransactor()
     if err != nil {
@@ -543,12 +579,63 @@ func (t *submitNetworkBalances) submitBalances(balances networkBalances) error {
     }
 
     // Submit balances
-    if _, err := network.SubmitBalances(t.rp, balances.Block, totalEth, balances.MinipoolsStaking, balances.RETHSupply, opts); err != nil {
+    txReceipt, err := network.SubmitBalances(t.rp, balances.Block, totalEth, balances.MinipoolsStaking, balances.RETHSupply, opts)
+    metrics.RecordTransaction("submitNetworkBalances", err)
+    recordSubmission(t.db, t.log, "submitNetworkBalances", "submitBalances", map[string]string{
+        "block": strconv.FormatUint(balances.Block, 10),
+        "totalEthWei": totalEth.String(),
+        "stakingEthWei": balances.MinipoolsStaking.String(),
+        "rethSupplyWei": balances.RETHSupply.String(),
+    }, txReceipt, err)
+    if err != nil {
+        return err
+    }
+    metrics.NetworkBalancesSubmittedBlock.Set(float64(balances.Block))
+
+    // Log
+    t.log.Info("Successfully submitted network balances.", "block", balances.Block, "txHash", txReceipt.TxHash.Hex())
+
+    // Return
+    return nil
+
+}
+
+
+// Simulate a network balances submission without sending it
+func (t *submitNetworkBalances) simulateBalances(balances networkBalances, totalEth *big.Int) error {
+
+    // Get transactor
+    opts, err := t.w.GetNodeAccountTransactor()
+    if err != nil {
+        return err
+    }
+
+    // Get contract
+    rocketNetworkBalances, err := t.rp.GetContract("rocketNetworkBalances")
+    if err != nil {
         return err
     }
 
+    // Simulate submission
+    gasEstimate, err := rp.SimulateTransaction(rocketNetworkBalances, opts, "submitBalances", big.NewInt(int64(balances.Block)), totalEth, balances.MinipoolsStaking, balances.RETHSupply)
+    if err != nil {
+        return err
+    }
+
+    // Get expected rETH exchange rate
+    exchangeRate := float64(1)
+    if balances.RETHSupply.Cmp(big.NewInt(0)) > 0 {
+        exchangeRate = eth.WeiToEth(totalEth) / eth.WeiToEth(balances.RETHSupply)
+    }
+
     // Log
-    t.log.Printlnf("Successfully submitted network balances for block %d.", balances.Block)
+    t.log.Info("Dry run: network balances submission would succeed; transaction not sent.",
+        "block", balances.Block,
+        "totalEthWei", totalEth.String(),
+        "stakingEthWei", balances.MinipoolsStaking.String(),
+        "rethSupplyWei", balances.RETHSupply.String(),
+        "expectedExchangeRate", exchangeRate,
+        "gasEstimate", gasEstimate)
 
     // Return
     return nil
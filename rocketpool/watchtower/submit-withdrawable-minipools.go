@@ -1,6 +1,7 @@
 package watchtower
 
 import (
+    "errors"
     "fmt"
     "math/big"
 
@@ -17,6 +18,8 @@ import (
 
     "github.com/rocket-pool/smartnode/shared/services"
     "github.com/rocket-pool/smartnode/shared/services/beacon"
+    "github.com/rocket-pool/smartnode/shared/services/database"
+    "github.com/rocket-pool/smartnode/shared/services/metrics"
     "github.com/rocket-pool/smartnode/shared/services/wallet"
     "github.com/rocket-pool/smartnode/shared/utils/eth2"
     "github.com/rocket-pool/smartnode/shared/utils/log"
@@ -31,10 +34,12 @@ const MinipoolWithdrawableDetailsBatchSize = 20
 // Submit withdrawable minipools task
 type submitWithdrawableMinipools struct {
     c *cli.Context
-    log log.ColorLogger
+    log log.Logger
+    dryRun bool
     w *wallet.Wallet
     rp *rocketpool.RocketPool
     bc beacon.Client
+    db *database.Database
 }
 
 
@@ -48,7 +53,7 @@ type minipoolWithdrawableDetails struct {
 
 
 // Create submit withdrawable minipools task
-func newSubmitWithdrawableMinipools(c *cli.Context, logger log.ColorLogger) (*submitWithdrawableMinipools, error) {
+func newSubmitWithdrawableMinipools(c *cli.Context, logger log.Logger) (*submitWithdrawableMinipools, error) {
 
     // Get services
     w, err := services.GetWallet(c)
@@ -57,14 +62,18 @@ func newSubmitWithdrawableMinipools(c *cli.Context, logger log.ColorLogger) (*su
     if err != nil { return nil, err }
     bc, err := services.GetBeaconClient(c)
     if err != nil { return nil, err }
+    db, err := services.GetDatabase(c)
+    if err != nil { return nil, err }
 
     // Return task
     return &submitWithdrawableMinipools{
         c: c,
         log: logger,
+        dryRun: c.Bool("dry-run"),
         w: w,
         rp: rp,
         bc: bc,
+        db: db,
     }, nil
 
 }
@@ -115,7 +124,7 @@ func (t *submitWithdrawableMinipools) run() error {
     }
 
     // Log
-    t.log.Println("Checking for withdrawable minipools...")
+    t.log.Info("Checking for withdrawable minipools...")
 
     // Get minipool withdrawable details
     minipools, err := t.getNetworkMinipoolWithdrawableDetails(nodeAccount.Address)
@@ -127,12 +136,12 @@ func (t *submitWithdrawableMinipools) run() error {
     }
 
     // Log
-    t.log.Printlnf("%d minipools are withdrawable...", len(minipools))
+    t.log.Info("Minipools are withdrawable...", "count", len(minipools))
 
     // Submit minipools withdrawable status
     for _, details := range minipools {
         if err := t.submitWithdrawableMinipool(details); err != nil {
-            t.log.Println(fmt.Errorf("Could not submit minipool %s withdrawable status: %w", details.Address.Hex(), err))
+            t.log.Error("Could not submit minipool withdrawable status", "minipool", details.Address.Hex(), "error", err)
         }
     }
 
@@ -178,7 +187,7 @@ func (t *submitWithdrawableMinipools) getNetworkMinipoolWithdrawableDetails(node
     }
 
     // Get minipool validator statuses
-    validators, err := rp.GetMinipoolValidators(t.rp, t.bc, addresses, nil, nil)
+    validators, err := rp.GetMinipoolValidators(t.rp, t.bc, t.db, addresses, nil, nil)
     if err != nil {
         return []minipoolWithdrawableDetails{}, err
     }
@@ -213,6 +222,11 @@ func (t *submitWithdrawableMinipools) getNetworkMinipoolWithdrawableDetails(node
 
     }
 
+    // Save cached minipool deposits
+    if err := t.db.SaveMinipools(); err != nil {
+        return []minipoolWithdrawableDetails{}, err
+    }
+
     // Filter by withdrawable status
     withdrawableMinipools := []minipoolWithdrawableDetails{}
     for _, details := range minipools {
@@ -236,43 +250,11 @@ func (t *submitWithdrawableMinipools) getMinipoolWithdrawableDetails(nodeAddress
         return minipoolWithdrawableDetails{}, err
     }
 
-    // Data
-    var wg errgroup.Group
-    var status types.MinipoolStatus
-    var nodeDepositBalance *big.Int
-    var userDepositBalance *big.Int
-    var userDepositTime uint64
-
-    // Load data
-    wg.Go(func() error {
-        var err error
-        status, err = mp.GetStatus(nil)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        nodeDepositBalance, err = mp.GetNodeDepositBalance(nil)
-        return err
-    })
-    wg.Go(func() error {
-        var err error
-        userDepositBalance, err = mp.GetUserDepositBalance(nil)
-        return err
-    })
-    wg.Go(func() error {
-        userDepositAssignedTime, err := mp.GetUserDepositAssignedTime(nil)
-        if err == nil {
-            userDepositTime = uint64(userDepositAssignedTime.Unix())
-        }
-        return err
-    })
-
-    // Wait for data
-    if err := wg.Wait(); err != nil {
+    // Get & check minipool status
+    status, err := mp.GetStatus(nil)
+    if err != nil {
         return minipoolWithdrawableDetails{}, err
     }
-
-    // Check minipool status
     if status != types.Staking {
         return minipoolWithdrawableDetails{}, nil
     }
@@ -282,6 +264,15 @@ func (t *submitWithdrawableMinipools) getMinipoolWithdrawableDetails(nodeAddress
         return minipoolWithdrawableDetails{}, nil
     }
 
+    // Get deposits
+    deposits, err := rp.GetMinipoolDeposits(mp, t.db, status, nil)
+    if err != nil {
+        return minipoolWithdrawableDetails{}, err
+    }
+    nodeDepositBalance := deposits.NodeDepositBalance
+    userDepositBalance := deposits.UserDepositBalance
+    userDepositTime := deposits.UserDepositTime
+
     // Get start epoch for node balance calculation
     startEpoch := eth2.EpochAt(eth2Config, userDepositTime)
     if startEpoch < validator.ActivationEpoch {
@@ -295,8 +286,11 @@ func (t *submitWithdrawableMinipools) getMinipoolWithdrawableDetails(nodeAddress
     activationBalanceWei.Add(nodeDepositBalance, userDepositBalance)
     activationBalance := eth.WeiToGwei(activationBalanceWei)
 
-    // Calculate approximate validator balance at start epoch & validator balance at current epoch
-    startBalance := eth.GweiToWei(activationBalance + (float64(validator.Balance) - activationBalance) * float64(startEpoch - validator.ActivationEpoch) / float64(beaconHead.FinalizedEpoch - validator.ActivationEpoch))
+    // Get validator balance at start epoch & validator balance at current epoch
+    startBalance, err := t.getValidatorStartBalance(validator, startEpoch, activationBalance, beaconHead)
+    if err != nil {
+        return minipoolWithdrawableDetails{}, err
+    }
     endBalance := eth.GweiToWei(float64(validator.Balance))
 
     // Check for existing node submission
@@ -323,11 +317,76 @@ func (t *submitWithdrawableMinipools) getMinipoolWithdrawableDetails(nodeAddress
 }
 
 
+// Get a validator's balance at the start epoch from the beacon state
+// Falls back to interpolating between the activation & current balances if the beacon node no longer has the state
+func (t *submitWithdrawableMinipools) getValidatorStartBalance(validator beacon.ValidatorStatus, startEpoch uint64, activationBalance float64, beaconHead beacon.BeaconHead) (*big.Int, error) {
+
+    // Get validator balance at start epoch
+    balances, err := t.bc.GetValidatorBalances([]types.ValidatorPubkey{validator.Pubkey}, &beacon.ValidatorBalanceOptions{Epoch: startEpoch})
+    if err == nil {
+        balance, ok := balances[validator.Pubkey]
+        if !ok {
+            return nil, fmt.Errorf("Validator %s balance at epoch %d not found", validator.Pubkey.Hex(), startEpoch)
+        }
+        return eth.GweiToWei(float64(balance)), nil
+    }
+    if !errors.Is(err, beacon.ErrStateUnavailable) {
+        return nil, err
+    }
+
+    // Log
+    t.log.Warn("Beacon state is unavailable, approximating validator start balance...", "validator", validator.Pubkey.Hex(), "epoch", startEpoch)
+
+    // Calculate approximate validator balance at start epoch
+    return eth.GweiToWei(activationBalance + (float64(validator.Balance) - activationBalance) * float64(startEpoch - validator.ActivationEpoch) / float64(beaconHead.FinalizedEpoch - validator.ActivationEpoch)), nil
+
+}
+
+
 // Submit minipool withdrawable status
 func (t *submitWithdrawableMinipools) submitWithdrawableMinipool(details minipoolWithdrawableDetails) error {
 
     // Log
-    t.log.Printlnf("Submitting minipool %s withdrawable status...", details.Address.Hex())
+    t.log.Info("Submitting minipool withdrawable status...", "minipool", details.Address.Hex())
+
+    // Simulate submission in dry-run mode
+    if t.dryRun {
+        return t.simulateWithdrawableMinipool(details)
+    }
+
+    // Lock transactions
+    txLock.Lock()
+    defer txLock.Unlock()
+
+    // Get transactor
+    opts, err := t.w.GetNodeAccountTransactor()
+    if err != nil {
+        return err
+    }
+
+    // Submit withdrawable status
+    txReceipt, err := minipool.SubmitMinipoolWithdrawable(t.rp, details.Address, details.StartBalance, details.EndBalance, opts)
+    metrics.RecordTransaction("submitWithdrawableMinipools", err)
+    recordSubmission(t.db, t.log, "submitWithdrawableMinipools", "submitMinipoolWithdrawable", map[string]string{
+        "minipool": details.Address.Hex(),
+        "stakingStartBalanceWei": details.StartBalance.String(),
+        "stakingEndBalanceWei": details.EndBalance.String(),
+    }, txReceipt, err)
+    if err != nil {
+        return err
+    }
+
+    // Log
+    t.log.Info("Successfully submitted minipool withdrawable status.", "minipool", details.Address.Hex(), "txHash", txReceipt.TxHash.Hex())
+
+    // Return
+    return nil
+
+}
+
+
+// Simulate a minipool withdrawable status submission without sending it
+func (t *submitWithdrawableMinipools) simulateWithdrawableMinipool(details minipoolWithdrawableDetails) error {
 
     // Get transactor
     opts, err := t.w.GetNodeAccountTransactor()
@@ -335,13 +394,24 @@ func (t *submitWithdrawableMinipools) submitWithdrawableMinipool(details minipoo
         return err
     }
 
-    // Dissolve
-    if _, err := minipool.SubmitMinipoolWithdrawable(t.rp, details.Address, details.StartBalance, details.EndBalance, opts); err != nil {
+    // Get contract
+    rocketMinipoolStatus, err := t.rp.GetContract("rocketMinipoolStatus")
+    if err != nil {
+        return err
+    }
+
+    // Simulate submission
+    gasEstimate, err := rp.SimulateTransaction(rocketMinipoolStatus, opts, "submitMinipoolWithdrawable", details.Address, details.StartBalance, details.EndBalance)
+    if err != nil {
         return err
     }
 
     // Log
-    t.log.Printlnf("Successfully submitted minipool %s withdrawable status.", details.Address.Hex())
+    t.log.Info("Dry run: minipool withdrawable status submission would succeed; transaction not sent.",
+        "minipool", details.Address.Hex(),
+        "stakingStartBalanceWei", details.StartBalance.String(),
+        "stakingEndBalanceWei", details.EndBalance.String(),
+        "gasEstimate", gasEstimate)
 
     // Return
     return nil
@@ -2,19 +2,30 @@ package watchtower
 
 import (
     "net/http"
+    "sync"
     "time"
 
+    "github.com/ethereum/go-ethereum/core/types"
     "github.com/fatih/color"
     "github.com/urfave/cli"
 
     "github.com/rocket-pool/smartnode/shared/services"
+    "github.com/rocket-pool/smartnode/shared/services/beacon"
+    "github.com/rocket-pool/smartnode/shared/services/database"
+    "github.com/rocket-pool/smartnode/shared/services/events"
+    "github.com/rocket-pool/smartnode/shared/services/metrics"
     "github.com/rocket-pool/smartnode/shared/utils/log"
+    "github.com/rocket-pool/smartnode/shared/utils/scheduler"
 )
 
 
 // Config
-var tasksInterval, _ = time.ParseDuration("5m")
-var taskCooldown, _ = time.ParseDuration("10s")
+var updateMetricsSettings = scheduler.TaskSettings{Interval: 1 * time.Minute, Timeout: 5 * time.Minute}
+var updateNetworkMetricsSettings = scheduler.TaskSettings{Interval: 5 * time.Minute, Jitter: 30 * time.Second, Timeout: 10 * time.Minute}
+var submitNetworkBalancesSettings = scheduler.TaskSettings{Interval: 5 * time.Minute, Jitter: 30 * time.Second, Timeout: 20 * time.Minute}
+var submitWithdrawableMinipoolsSettings = scheduler.TaskSettings{Interval: 5 * time.Minute, Jitter: 30 * time.Second, Timeout: 20 * time.Minute}
+var dissolveTimedOutMinipoolsSettings = scheduler.TaskSettings{Interval: 5 * time.Minute, Jitter: 30 * time.Second, Timeout: 10 * time.Minute}
+var processWithdrawalsSettings = scheduler.TaskSettings{Interval: 5 * time.Minute, Jitter: 30 * time.Second, Timeout: 20 * time.Minute}
 const (
     MaxConcurrentEth1Requests = 200
 
@@ -22,16 +33,60 @@ const (
     SubmitWithdrawableMinipoolsColor = color.FgBlue
     DissolveTimedOutMinipoolsColor = color.FgMagenta
     ProcessWithdrawalsColor = color.FgCyan
-    ErrorColor = color.FgRed
+    EventsColor = color.FgWhite
+    MetricsColor = color.FgGreen
+    SchedulerColor = color.FgRed
 )
 
 
+// Transaction lock
+// Tasks run concurrently and share the node account, so transactions are sent one at a time to prevent nonce collisions
+var txLock sync.Mutex
+
+
+// Record a transaction submission in the database journal
+// Journal errors are logged rather than returned so that they do not mask the submission result
+func recordSubmission(db *database.Database, logger log.Logger, task string, method string, args map[string]string, txReceipt *types.Receipt, txErr error) {
+
+    // Build submission record
+    submission := database.Submission{
+        Time: time.Now(),
+        Task: task,
+        Method: method,
+        Arguments: args,
+        Status: database.SubmissionSucceeded,
+    }
+    if txReceipt != nil {
+        submission.TxHash = txReceipt.TxHash
+        if txReceipt.BlockNumber != nil {
+            submission.Block = txReceipt.BlockNumber.Uint64()
+        }
+    }
+    if txErr != nil {
+        submission.Status = database.SubmissionFailed
+        submission.Error = txErr.Error()
+    }
+
+    // Add to journal
+    if err := db.AddSubmission(submission); err != nil {
+        logger.Warn("Could not record submission in database", "method", method, "txHash", submission.TxHash.Hex(), "error", err)
+    }
+
+}
+
+
 // Register watchtower command
 func RegisterCommands(app *cli.App, name string, aliases []string) {
     app.Commands = append(app.Commands, cli.Command{
         Name:      name,
         Aliases:   aliases,
         Usage:     "Run Rocket Pool watchtower activity daemon",
+        Flags: []cli.Flag{
+            cli.BoolFlag{
+                Name:  "dry-run, d",
+                Usage: "Simulate watchtower transactions and log their expected outcome without sending them",
+            },
+        },
         Action: func(c *cli.Context) error {
             return run(c)
         },
@@ -43,43 +98,72 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 func run(c *cli.Context) error {
 
     // Configure
+    if err := services.ConfigureLogger(c); err != nil { return err }
     configureHTTP()
 
     // Wait until node is registered
     if err := services.WaitNodeRegistered(c, true); err != nil { return err }
 
+    // Get services
+    cfg, err := services.GetConfig(c)
+    if err != nil { return err }
+    bc, err := services.GetBeaconClient(c)
+    if err != nil { return err }
+
     // Initialize tasks
-    submitNetworkBalances, err := newSubmitNetworkBalances(c, log.NewColorLogger(SubmitNetworkBalancesColor))
+    submitNetworkBalances, err := newSubmitNetworkBalances(c, log.NewLogger(SubmitNetworkBalancesColor).With("task", "submitNetworkBalances"))
     if err != nil { return err }
-    submitWithdrawableMinipools, err := newSubmitWithdrawableMinipools(c, log.NewColorLogger(SubmitWithdrawableMinipoolsColor))
+    submitWithdrawableMinipools, err := newSubmitWithdrawableMinipools(c, log.NewLogger(SubmitWithdrawableMinipoolsColor).With("task", "submitWithdrawableMinipools"))
     if err != nil { return err }
-    dissolveTimedOutMinipools, err := newDissolveTimedOutMinipools(c, log.NewColorLogger(DissolveTimedOutMinipoolsColor))
+    dissolveTimedOutMinipools, err := newDissolveTimedOutMinipools(c, log.NewLogger(DissolveTimedOutMinipoolsColor).With("task", "dissolveTimedOutMinipools"))
     if err != nil { return err }
-    processWithdrawals, err := newProcessWithdrawals(c, log.NewColorLogger(ProcessWithdrawalsColor))
+    processWithdrawals, err := newProcessWithdrawals(c, log.NewLogger(ProcessWithdrawalsColor).With("task", "processWithdrawals"))
     if err != nil { return err }
 
-    // Initialize error logger
-    errorLog := log.NewColorLogger(ErrorColor)
+    // Initialize scheduler
+    schedulerLog := log.NewLogger(SchedulerColor)
+    if c.Bool("dry-run") {
+        schedulerLog.Warn("Running in dry-run mode; watchtower transactions will be simulated and not sent.")
+    }
+    s := scheduler.NewScheduler(schedulerLog)
+    s.AddTask("submitNetworkBalances", submitNetworkBalances.run, submitNetworkBalancesSettings)
+    s.AddTask("submitWithdrawableMinipools", submitWithdrawableMinipools.run, submitWithdrawableMinipoolsSettings)
+    s.AddTask("dissolveTimedOutMinipools", dissolveTimedOutMinipools.run, dissolveTimedOutMinipoolsSettings)
+    s.AddTask("processWithdrawals", processWithdrawals.run, processWithdrawalsSettings)
+    if cfg.Smartnode.MetricsAddress != "" {
+        s.AddTask("updateMetrics", func() error { return metrics.UpdateNodeMetrics(c) }, updateMetricsSettings)
+        s.AddTask("updateNetworkMetrics", func() error { return metrics.UpdateNetworkMetrics(c) }, updateNetworkMetricsSettings)
+    }
 
-    // Run task loop
-    for {
-        if err := submitNetworkBalances.run(); err != nil {
-            errorLog.Println(err)
-        }
-        time.Sleep(taskCooldown)
-        if err := submitWithdrawableMinipools.run(); err != nil {
-            errorLog.Println(err)
-        }
-        time.Sleep(taskCooldown)
-        if err := dissolveTimedOutMinipools.run(); err != nil {
-            errorLog.Println(err)
+    // Trigger network balance submission when the reportable block changes
+    eventsLog := log.NewLogger(EventsColor)
+    eth1Watcher := events.NewEth1HeadWatcher(cfg.Chains.Eth1.WsProvider, eventsLog)
+    eth1Watcher.OnNewHead(func(header *types.Header) {
+        changed, err := submitNetworkBalances.reportableBlockChanged(header.Number.Uint64())
+        if err != nil {
+            eventsLog.Error("Could not check network balances reportable block", "block", header.Number, "error", err)
+            return
         }
-        time.Sleep(taskCooldown)
-        if err := processWithdrawals.run(); err != nil {
-            errorLog.Println(err)
+        if changed {
+            s.Trigger("submitNetworkBalances")
         }
-        time.Sleep(tasksInterval)
-    }
+    })
+
+    // Trigger withdrawal tasks when beacon finality changes
+    finalityWatcher := events.NewBeaconFinalityWatcher(bc, eventsLog)
+    finalityWatcher.OnFinalityChange(func(head beacon.BeaconHead) {
+        s.Trigger("submitWithdrawableMinipools")
+        s.Trigger("processWithdrawals")
+    })
+
+    // Start tasks, event watchers & metrics server
+    s.Start()
+    eth1Watcher.Start()
+    finalityWatcher.Start()
+    metrics.Start(cfg.Smartnode.MetricsAddress, log.NewLogger(MetricsColor))
+
+    // Block thread
+    select {}
 
 }
 
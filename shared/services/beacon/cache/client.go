@@ -0,0 +1,466 @@
+package cache
+
+import (
+    "fmt"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/ethereum/go-ethereum/event"
+    "github.com/rocket-pool/rocketpool-go/types"
+    "golang.org/x/sync/singleflight"
+
+    "github.com/rocket-pool/smartnode/shared/services/beacon"
+)
+
+
+// Caching beacon client
+// Wraps a beacon client, caching responses and coalescing concurrent identical requests:
+// - the eth2 config is immutable and cached indefinitely
+// - validator indices are immutable once assigned and cached indefinitely
+// - the sync status and beacon head are cached for the current slot
+// - validator statuses are cached until the finalized epoch changes
+type Client struct {
+    client beacon.Client
+    group singleflight.Group
+    lock sync.Mutex
+
+    eth2Config *beacon.Eth2Config
+    validatorIndices map[types.ValidatorPubkey]uint64
+
+    syncStatus beacon.SyncStatus
+    syncStatusSlot uint64
+    syncStatusCached bool
+    head beacon.BeaconHead
+    headSlot uint64
+    headCached bool
+
+    statuses map[string]map[types.ValidatorPubkey]beacon.ValidatorStatus
+    statusesFinalizedEpoch uint64
+}
+
+
+// Create new caching beacon client
+func NewClient(client beacon.Client) *Client {
+    return &Client{
+        client: client,
+        validatorIndices: make(map[types.ValidatorPubkey]uint64),
+        statuses: make(map[string]map[types.ValidatorPubkey]beacon.ValidatorStatus),
+    }
+}
+
+
+// Close the underlying client connection
+func (c *Client) Close() {
+    c.client.Close()
+}
+
+
+// Get the beacon client type
+func (c *Client) GetClientType() (beacon.BeaconClientType) {
+    return c.client.GetClientType()
+}
+
+
+// Get the node's sync status
+// Cached for the current slot
+func (c *Client) GetSyncStatus() (beacon.SyncStatus, error) {
+
+    // Get current slot
+    slot, err := c.getCurrentSlot()
+    if err != nil {
+        return beacon.SyncStatus{}, err
+    }
+
+    // Check cache
+    c.lock.Lock()
+    if c.syncStatusCached && c.syncStatusSlot == slot {
+        syncStatus := c.syncStatus
+        c.lock.Unlock()
+        return syncStatus, nil
+    }
+    c.lock.Unlock()
+
+    // Get & cache sync status
+    result, err, _ := c.group.Do(fmt.Sprintf("syncStatus:%d", slot), func() (interface{}, error) {
+        syncStatus, err := c.client.GetSyncStatus()
+        if err != nil {
+            return nil, err
+        }
+        c.lock.Lock()
+        c.syncStatus = syncStatus
+        c.syncStatusSlot = slot
+        c.syncStatusCached = true
+        c.lock.Unlock()
+        return syncStatus, nil
+    })
+    if err != nil {
+        return beacon.SyncStatus{}, err
+    }
+    return result.(beacon.SyncStatus), nil
+
+}
+
+
+// Get the eth2 config
+// Cached indefinitely once retrieved
+func (c *Client) GetEth2Config() (beacon.Eth2Config, error) {
+
+    // Check cache
+    c.lock.Lock()
+    if c.eth2Config != nil {
+        eth2Config := *c.eth2Config
+        c.lock.Unlock()
+        return eth2Config, nil
+    }
+    c.lock.Unlock()
+
+    // Get & cache eth2 config
+    result, err, _ := c.group.Do("eth2Config", func() (interface{}, error) {
+        eth2Config, err := c.client.GetEth2Config()
+        if err != nil {
+            return nil, err
+        }
+        c.lock.Lock()
+        c.eth2Config = &eth2Config
+        c.lock.Unlock()
+        return eth2Config, nil
+    })
+    if err != nil {
+        return beacon.Eth2Config{}, err
+    }
+    return result.(beacon.Eth2Config), nil
+
+}
+
+
+// Get the genesis fork version
+// Not cached, as it is used to verify the cached eth2 config
+func (c *Client) GetGenesisForkVersion() ([]byte, error) {
+    return c.client.GetGenesisForkVersion()
+}
+
+
+// Get the beacon head
+// Cached for the current slot
+func (c *Client) GetBeaconHead() (beacon.BeaconHead, error) {
+
+    // Get current slot
+    slot, err := c.getCurrentSlot()
+    if err != nil {
+        return beacon.BeaconHead{}, err
+    }
+
+    // Check cache
+    c.lock.Lock()
+    if c.headCached && c.headSlot == slot {
+        head := c.head
+        c.lock.Unlock()
+        return head, nil
+    }
+    c.lock.Unlock()
+
+    // Get & cache beacon head
+    result, err, _ := c.group.Do(fmt.Sprintf("head:%d", slot), func() (interface{}, error) {
+        head, err := c.client.GetBeaconHead()
+        if err != nil {
+            return nil, err
+        }
+        c.lock.Lock()
+        c.head = head
+        c.headSlot = slot
+        c.headCached = true
+        c.lock.Unlock()
+        return head, nil
+    })
+    if err != nil {
+        return beacon.BeaconHead{}, err
+    }
+    return result.(beacon.BeaconHead), nil
+
+}
+
+
+// Get a validator's status
+func (c *Client) GetValidatorStatus(pubkey types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
+    statuses, err := c.GetValidatorStatuses([]types.ValidatorPubkey{pubkey}, opts)
+    if err != nil {
+        return beacon.ValidatorStatus{}, err
+    }
+    return statuses[pubkey], nil
+}
+
+
+// Get multiple validators' statuses
+// Cached until the finalized epoch changes; only validators missing from the cache are requested
+func (c *Client) GetValidatorStatuses(pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (map[types.ValidatorPubkey]beacon.ValidatorStatus, error) {
+
+    // Get finalized epoch
+    head, err := c.GetBeaconHead()
+    if err != nil {
+        return map[types.ValidatorPubkey]beacon.ValidatorStatus{}, err
+    }
+
+    // Get cache key
+    stateKey := "head"
+    if opts != nil {
+        stateKey = fmt.Sprintf("epoch:%d", opts.Epoch)
+    }
+
+    // Get cached statuses & missing validators
+    statuses := make(map[types.ValidatorPubkey]beacon.ValidatorStatus, len(pubkeys))
+    missing := []types.ValidatorPubkey{}
+    c.lock.Lock()
+    if c.statusesFinalizedEpoch != head.FinalizedEpoch {
+        c.statuses = make(map[string]map[types.ValidatorPubkey]beacon.ValidatorStatus)
+        c.statusesFinalizedEpoch = head.FinalizedEpoch
+    }
+    cached := c.statuses[stateKey]
+    for _, pubkey := range pubkeys {
+        if status, ok := cached[pubkey]; ok {
+            statuses[pubkey] = status
+        } else {
+            missing = append(missing, pubkey)
+        }
+    }
+    c.lock.Unlock()
+    if len(missing) == 0 {
+        return statuses, nil
+    }
+
+    // Get & cache missing statuses
+    key := fmt.Sprintf("statuses:%d:%s:%s", head.FinalizedEpoch, stateKey, getPubkeysKey(missing))
+    result, err, _ := c.group.Do(key, func() (interface{}, error) {
+        missingStatuses, err := c.client.GetValidatorStatuses(missing, opts)
+        if err != nil {
+            return nil, err
+        }
+        c.lock.Lock()
+        if c.statusesFinalizedEpoch == head.FinalizedEpoch {
+            if _, ok := c.statuses[stateKey]; !ok {
+                c.statuses[stateKey] = make(map[types.ValidatorPubkey]beacon.ValidatorStatus)
+            }
+            for pubkey, status := range missingStatuses {
+                c.statuses[stateKey][pubkey] = status
+            }
+        }
+        c.lock.Unlock()
+        return missingStatuses, nil
+    })
+    if err != nil {
+        return map[types.ValidatorPubkey]beacon.ValidatorStatus{}, err
+    }
+    for pubkey, status := range result.(map[types.ValidatorPubkey]beacon.ValidatorStatus) {
+        statuses[pubkey] = status
+    }
+
+    // Return
+    return statuses, nil
+
+}
+
+
+// Get multiple validators' balances at a beacon state
+// Concurrent identical requests are coalesced
+func (c *Client) GetValidatorBalances(pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorBalanceOptions) (map[types.ValidatorPubkey]uint64, error) {
+    key := fmt.Sprintf("balances:%s", getPubkeysKey(pubkeys))
+    if opts != nil {
+        key = fmt.Sprintf("%s:%d:%s", key, opts.Epoch, opts.StateId)
+    }
+    result, err, _ := c.group.Do(key, func() (interface{}, error) {
+        return c.client.GetValidatorBalances(pubkeys, opts)
+    })
+    if err != nil {
+        return map[types.ValidatorPubkey]uint64{}, err
+    }
+    return copyBalances(result.(map[types.ValidatorPubkey]uint64)), nil
+}
+
+
+// Get a validator's index
+// Cached indefinitely once retrieved
+func (c *Client) GetValidatorIndex(pubkey types.ValidatorPubkey) (uint64, error) {
+
+    // Check cache
+    c.lock.Lock()
+    if index, ok := c.validatorIndices[pubkey]; ok {
+        c.lock.Unlock()
+        return index, nil
+    }
+    c.lock.Unlock()
+
+    // Get & cache validator index
+    result, err, _ := c.group.Do(fmt.Sprintf("index:%s", pubkey.Hex()), func() (interface{}, error) {
+        index, err := c.client.GetValidatorIndex(pubkey)
+        if err != nil {
+            return nil, err
+        }
+        c.lock.Lock()
+        c.validatorIndices[pubkey] = index
+        c.lock.Unlock()
+        return index, nil
+    })
+    if err != nil {
+        return 0, err
+    }
+    return result.(uint64), nil
+
+}
+
+
+// Get multiple validators' balances at the start of each epoch in a range
+// Concurrent identical requests are coalesced
+func (c *Client) GetValidatorBalanceHistory(pubkeys []types.ValidatorPubkey, startEpoch, endEpoch uint64) (map[types.ValidatorPubkey][]uint64, error) {
+    key := fmt.Sprintf("balanceHistory:%d:%d:%s", startEpoch, endEpoch, getPubkeysKey(pubkeys))
+    result, err, _ := c.group.Do(key, func() (interface{}, error) {
+        return c.client.GetValidatorBalanceHistory(pubkeys, startEpoch, endEpoch)
+    })
+    if err != nil {
+        return map[types.ValidatorPubkey][]uint64{}, err
+    }
+    history := make(map[types.ValidatorPubkey][]uint64)
+    for pubkey, balances := range result.(map[types.ValidatorPubkey][]uint64) {
+        history[pubkey] = append([]uint64{}, balances...)
+    }
+    return history, nil
+}
+
+
+// Get multiple validators' attester duties for an epoch
+// Concurrent identical requests are coalesced
+func (c *Client) GetAttesterDuties(pubkeys []types.ValidatorPubkey, epoch uint64) ([]beacon.AttesterDuty, error) {
+    key := fmt.Sprintf("attesterDuties:%d:%s", epoch, getPubkeysKey(pubkeys))
+    result, err, _ := c.group.Do(key, func() (interface{}, error) {
+        return c.client.GetAttesterDuties(pubkeys, epoch)
+    })
+    if err != nil {
+        return []beacon.AttesterDuty{}, err
+    }
+    return append([]beacon.AttesterDuty{}, result.([]beacon.AttesterDuty)...), nil
+}
+
+
+// Get multiple validators' proposer duties for an epoch
+// Concurrent identical requests are coalesced
+func (c *Client) GetProposerDuties(pubkeys []types.ValidatorPubkey, epoch uint64) ([]beacon.ProposerDuty, error) {
+    key := fmt.Sprintf("proposerDuties:%d:%s", epoch, getPubkeysKey(pubkeys))
+    result, err, _ := c.group.Do(key, func() (interface{}, error) {
+        return c.client.GetProposerDuties(pubkeys, epoch)
+    })
+    if err != nil {
+        return []beacon.ProposerDuty{}, err
+    }
+    return append([]beacon.ProposerDuty{}, result.([]beacon.ProposerDuty)...), nil
+}
+
+
+// Get multiple validators' attestation & proposal performance for an epoch
+// Concurrent identical requests are coalesced
+func (c *Client) GetValidatorPerformance(pubkeys []types.ValidatorPubkey, epoch uint64) (map[types.ValidatorPubkey]beacon.ValidatorPerformance, error) {
+    key := fmt.Sprintf("performance:%d:%s", epoch, getPubkeysKey(pubkeys))
+    result, err, _ := c.group.Do(key, func() (interface{}, error) {
+        return c.client.GetValidatorPerformance(pubkeys, epoch)
+    })
+    if err != nil {
+        return map[types.ValidatorPubkey]beacon.ValidatorPerformance{}, err
+    }
+    performance := make(map[types.ValidatorPubkey]beacon.ValidatorPerformance)
+    for pubkey, vp := range result.(map[types.ValidatorPubkey]beacon.ValidatorPerformance) {
+        performance[pubkey] = vp
+    }
+    return performance, nil
+}
+
+
+// Get domain data for a domain type at a given epoch
+// Concurrent identical requests are coalesced
+func (c *Client) GetDomainData(domainType []byte, epoch uint64) ([]byte, error) {
+    result, err, _ := c.group.Do(fmt.Sprintf("domainData:%x:%d", domainType, epoch), func() (interface{}, error) {
+        return c.client.GetDomainData(domainType, epoch)
+    })
+    if err != nil {
+        return []byte{}, err
+    }
+    return append([]byte{}, result.([]byte)...), nil
+}
+
+
+// Perform a voluntary exit on a validator
+func (c *Client) ExitValidator(validatorIndex, epoch uint64, signature types.ValidatorSignature) error {
+    return c.client.ExitValidator(validatorIndex, epoch, signature)
+}
+
+
+// Subscribe to beacon chain events
+// Head, finality & reorg events invalidate the cached sync status and beacon head before they are forwarded
+func (c *Client) SubscribeEvents(topics []string, events chan<- beacon.Event) (event.Subscription, error) {
+
+    // Subscribe to underlying client events
+    clientEvents := make(chan beacon.Event)
+    sub, err := c.client.SubscribeEvents(topics, clientEvents)
+    if err != nil {
+        return nil, err
+    }
+
+    // Forward events
+    return event.NewSubscription(func(quit <-chan struct{}) error {
+        defer sub.Unsubscribe()
+        for {
+            select {
+                case e := <-clientEvents:
+                    switch e.Topic {
+                        case beacon.EventTopicHead, beacon.EventTopicFinalizedCheckpoint, beacon.EventTopicChainReorg:
+                            c.lock.Lock()
+                            c.syncStatusCached = false
+                            c.headCached = false
+                            c.lock.Unlock()
+                    }
+                    select {
+                        case events <- e:
+                        case <-quit:
+                            return nil
+                    }
+                case err := <-sub.Err():
+                    return err
+                case <-quit:
+                    return nil
+            }
+        }
+    }), nil
+
+}
+
+
+// Get the current beacon chain slot from the genesis time
+func (c *Client) getCurrentSlot() (uint64, error) {
+    eth2Config, err := c.GetEth2Config()
+    if err != nil {
+        return 0, err
+    }
+    now := uint64(time.Now().Unix())
+    if now < eth2Config.GenesisTime || eth2Config.SecondsPerSlot == 0 {
+        return 0, nil
+    }
+    return (now - eth2Config.GenesisTime) / eth2Config.SecondsPerSlot, nil
+}
+
+
+// Get a request key for a set of validator pubkeys
+func getPubkeysKey(pubkeys []types.ValidatorPubkey) string {
+    var key strings.Builder
+    for _, pubkey := range pubkeys {
+        key.Write(pubkey.Bytes())
+    }
+    return key.String()
+}
+
+
+// Copy a validator balance map so that coalesced callers do not share it
+func copyBalances(balances map[types.ValidatorPubkey]uint64) map[types.ValidatorPubkey]uint64 {
+    balancesCopy := make(map[types.ValidatorPubkey]uint64, len(balances))
+    for pubkey, balance := range balances {
+        balancesCopy[pubkey] = balance
+    }
+    return balancesCopy
+}
+
@@ -1,7 +1,10 @@
 package beacon
 
 import (
+    "errors"
+
     "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/event"
     "github.com/rocket-pool/rocketpool-go/types"
 )
 
@@ -10,6 +13,24 @@ import (
 type ValidatorStatusOptions struct {
     Epoch uint64
 }
+type ValidatorBalanceOptions struct {
+    Epoch uint64
+    StateId string // Beacon state ID (slot, state root or named state); overrides Epoch if set
+}
+
+
+// Event stream topics
+const (
+    EventTopicHead = "head"
+    EventTopicFinalizedCheckpoint = "finalized_checkpoint"
+    EventTopicChainReorg = "chain_reorg"
+    EventTopicVoluntaryExit = "voluntary_exit"
+)
+
+
+// Errors
+var ErrStateUnavailable = errors.New("The requested beacon state is not available on the beacon node")
+var ErrProviderUnavailable = errors.New("The beacon node could not be reached or failed to handle the request")
 
 
 // API response types
@@ -21,6 +42,8 @@ type Eth2Config struct {
     GenesisValidatorsRoot []byte
     GenesisEpoch uint64
     GenesisTime uint64
+    SecondsPerSlot uint64
+    SlotsPerEpoch uint64
     SecondsPerEpoch uint64
 }
 type BeaconHead struct {
@@ -41,6 +64,33 @@ type ValidatorStatus struct {
     WithdrawableEpoch uint64
     Exists bool
 }
+type AttesterDuty struct {
+    Pubkey types.ValidatorPubkey
+    ValidatorIndex uint64
+    Slot uint64
+    CommitteeIndex uint64
+    CommitteePosition uint64
+}
+type ProposerDuty struct {
+    Pubkey types.ValidatorPubkey
+    ValidatorIndex uint64
+    Slot uint64
+}
+type Event struct {
+    Topic string
+    Slot uint64                     // head, chain_reorg
+    Epoch uint64                    // finalized_checkpoint, chain_reorg, voluntary_exit
+    BlockRoot common.Hash           // head, finalized_checkpoint, chain_reorg (new head)
+    Depth uint64                    // chain_reorg
+    ValidatorIndex uint64           // voluntary_exit
+}
+type ValidatorPerformance struct {
+    AttestationDuty bool
+    AttestationIncluded bool
+    InclusionDistance uint64
+    ProposalSlots []uint64
+    MissedProposalSlots []uint64
+}
 
 
 // Beacon client type
@@ -63,12 +113,19 @@ type Client interface {
     GetClientType() (BeaconClientType)
     GetSyncStatus() (SyncStatus, error)
     GetEth2Config() (Eth2Config, error)
+    GetGenesisForkVersion() ([]byte, error)
     GetBeaconHead() (BeaconHead, error)
     GetValidatorStatus(pubkey types.ValidatorPubkey, opts *ValidatorStatusOptions) (ValidatorStatus, error)
     GetValidatorStatuses(pubkeys []types.ValidatorPubkey, opts *ValidatorStatusOptions) (map[types.ValidatorPubkey]ValidatorStatus, error)
+    GetValidatorBalances(pubkeys []types.ValidatorPubkey, opts *ValidatorBalanceOptions) (map[types.ValidatorPubkey]uint64, error)
     GetValidatorIndex(pubkey types.ValidatorPubkey) (uint64, error)
+    GetValidatorBalanceHistory(pubkeys []types.ValidatorPubkey, startEpoch, endEpoch uint64) (map[types.ValidatorPubkey][]uint64, error)
+    GetAttesterDuties(pubkeys []types.ValidatorPubkey, epoch uint64) ([]AttesterDuty, error)
+    GetProposerDuties(pubkeys []types.ValidatorPubkey, epoch uint64) ([]ProposerDuty, error)
+    GetValidatorPerformance(pubkeys []types.ValidatorPubkey, epoch uint64) (map[types.ValidatorPubkey]ValidatorPerformance, error)
     GetDomainData(domainType []byte, epoch uint64) ([]byte, error)
     ExitValidator(validatorIndex, epoch uint64, signature types.ValidatorSignature) error
+    SubscribeEvents(topics []string, events chan<- Event) (event.Subscription, error)
     Close()
 }
 
@@ -0,0 +1,382 @@
+package failover
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/ethereum/go-ethereum/event"
+    "github.com/rocket-pool/rocketpool-go/types"
+
+    "github.com/rocket-pool/smartnode/shared/services/beacon"
+)
+
+
+// Config
+const (
+    HealthCheckInterval = 30 * time.Second
+    MaxHeadDistance = 2 // epochs
+)
+
+
+// Failover beacon client
+// Routes each call to the best healthy provider in order, failing over to the next healthy provider on error
+// Providers are healthy if they are synced and their head epoch is within MaxHeadDistance of the most advanced provider
+// Providers are only marked unhealthy on call errors if they could not be reached or failed to handle the request
+type Client struct {
+    providers []string
+    clients []beacon.Client
+    lock sync.Mutex
+    checkLock sync.Mutex
+    healthy []bool
+    checkedAt time.Time
+}
+
+
+// Create new failover beacon client
+// Clients are given in provider priority order
+func NewClient(providers []string, clients []beacon.Client) *Client {
+    return &Client{
+        providers: providers,
+        clients: clients,
+        healthy: make([]bool, len(clients)),
+    }
+}
+
+
+// Close all client connections
+func (c *Client) Close() {
+    for _, client := range c.clients {
+        client.Close()
+    }
+}
+
+
+// Get the beacon client type
+// All providers are instances of the same client, so the primary provider's type is returned
+func (c *Client) GetClientType() (beacon.BeaconClientType) {
+    return c.clients[0].GetClientType()
+}
+
+
+// Get the node's sync status
+func (c *Client) GetSyncStatus() (beacon.SyncStatus, error) {
+    var syncStatus beacon.SyncStatus
+    err := c.call(func(client beacon.Client) error {
+        var err error
+        syncStatus, err = client.GetSyncStatus()
+        return err
+    })
+    return syncStatus, err
+}
+
+
+// Get the eth2 config
+func (c *Client) GetEth2Config() (beacon.Eth2Config, error) {
+    var eth2Config beacon.Eth2Config
+    err := c.call(func(client beacon.Client) error {
+        var err error
+        eth2Config, err = client.GetEth2Config()
+        return err
+    })
+    return eth2Config, err
+}
+
+
+// Get the genesis fork version
+func (c *Client) GetGenesisForkVersion() ([]byte, error) {
+    var genesisForkVersion []byte
+    err := c.call(func(client beacon.Client) error {
+        var err error
+        genesisForkVersion, err = client.GetGenesisForkVersion()
+        return err
+    })
+    return genesisForkVersion, err
+}
+
+
+// Get the beacon head
+func (c *Client) GetBeaconHead() (beacon.BeaconHead, error) {
+    var head beacon.BeaconHead
+    err := c.call(func(client beacon.Client) error {
+        var err error
+        head, err = client.GetBeaconHead()
+        return err
+    })
+    return head, err
+}
+
+
+// Get a validator's status
+func (c *Client) GetValidatorStatus(pubkey types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
+    var status beacon.ValidatorStatus
+    err := c.call(func(client beacon.Client) error {
+        var err error
+        status, err = client.GetValidatorStatus(pubkey, opts)
+        return err
+    })
+    return status, err
+}
+
+
+// Get multiple validators' statuses
+func (c *Client) GetValidatorStatuses(pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (map[types.ValidatorPubkey]beacon.ValidatorStatus, error) {
+    var statuses map[types.ValidatorPubkey]beacon.ValidatorStatus
+    err := c.call(func(client beacon.Client) error {
+        var err error
+        statuses, err = client.GetValidatorStatuses(pubkeys, opts)
+        return err
+    })
+    return statuses, err
+}
+
+
+// Get multiple validators' balances at a beacon state
+func (c *Client) GetValidatorBalances(pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorBalanceOptions) (map[types.ValidatorPubkey]uint64, error) {
+    var balances map[types.ValidatorPubkey]uint64
+    err := c.call(func(client beacon.Client) error {
+        var err error
+        balances, err = client.GetValidatorBalances(pubkeys, opts)
+        return err
+    })
+    return balances, err
+}
+
+
+// Get a validator's index
+func (c *Client) GetValidatorIndex(pubkey types.ValidatorPubkey) (uint64, error) {
+    var index uint64
+    err := c.call(func(client beacon.Client) error {
+        var err error
+        index, err = client.GetValidatorIndex(pubkey)
+        return err
+    })
+    return index, err
+}
+
+
+// Get multiple validators' balances at the start of each epoch in a range
+func (c *Client) GetValidatorBalanceHistory(pubkeys []types.ValidatorPubkey, startEpoch, endEpoch uint64) (map[types.ValidatorPubkey][]uint64, error) {
+    var history map[types.ValidatorPubkey][]uint64
+    err := c.call(func(client beacon.Client) error {
+        var err error
+        history, err = client.GetValidatorBalanceHistory(pubkeys, startEpoch, endEpoch)
+        return err
+    })
+    return history, err
+}
+
+
+// Get multiple validators' attester duties for an epoch
+func (c *Client) GetAttesterDuties(pubkeys []types.ValidatorPubkey, epoch uint64) ([]beacon.AttesterDuty, error) {
+    var duties []beacon.AttesterDuty
+    err := c.call(func(client beacon.Client) error {
+        var err error
+        duties, err = client.GetAttesterDuties(pubkeys, epoch)
+        return err
+    })
+    return duties, err
+}
+
+
+// Get multiple validators' proposer duties for an epoch
+func (c *Client) GetProposerDuties(pubkeys []types.ValidatorPubkey, epoch uint64) ([]beacon.ProposerDuty, error) {
+    var duties []beacon.ProposerDuty
+    err := c.call(func(client beacon.Client) error {
+        var err error
+        duties, err = client.GetProposerDuties(pubkeys, epoch)
+        return err
+    })
+    return duties, err
+}
+
+
+// Get multiple validators' attestation & proposal performance for an epoch
+func (c *Client) GetValidatorPerformance(pubkeys []types.ValidatorPubkey, epoch uint64) (map[types.ValidatorPubkey]beacon.ValidatorPerformance, error) {
+    var performance map[types.ValidatorPubkey]beacon.ValidatorPerformance
+    err := c.call(func(client beacon.Client) error {
+        var err error
+        performance, err = client.GetValidatorPerformance(pubkeys, epoch)
+        return err
+    })
+    return performance, err
+}
+
+
+// Get domain data for a domain type at a given epoch
+func (c *Client) GetDomainData(domainType []byte, epoch uint64) ([]byte, error) {
+    var domainData []byte
+    err := c.call(func(client beacon.Client) error {
+        var err error
+        domainData, err = client.GetDomainData(domainType, epoch)
+        return err
+    })
+    return domainData, err
+}
+
+
+// Perform a voluntary exit on a validator
+// The exit is broadcast to all providers, and succeeds if any provider accepts it
+func (c *Client) ExitValidator(validatorIndex, epoch uint64, signature types.ValidatorSignature) error {
+
+    // Broadcast exit
+    var wg sync.WaitGroup
+    errs := make([]error, len(c.clients))
+    for ci, client := range c.clients {
+        wg.Add(1)
+        go func(ci int, client beacon.Client) {
+            defer wg.Done()
+            errs[ci] = client.ExitValidator(validatorIndex, epoch, signature)
+        }(ci, client)
+    }
+    wg.Wait()
+
+    // Check results
+    messages := []string{}
+    for ci, err := range errs {
+        if err == nil {
+            return nil
+        }
+        messages = append(messages, fmt.Sprintf("%s: %s", c.providers[ci], err.Error()))
+    }
+    return fmt.Errorf("Could not broadcast exit to any provider: %s", strings.Join(messages, "; "))
+
+}
+
+
+// Subscribe to beacon chain events from the best healthy provider
+// Subscribers should resubscribe when the subscription fails, to fail over to the next provider
+func (c *Client) SubscribeEvents(topics []string, events chan<- beacon.Event) (event.Subscription, error) {
+    var sub event.Subscription
+    err := c.call(func(client beacon.Client) error {
+        var err error
+        sub, err = client.SubscribeEvents(topics, events)
+        return err
+    })
+    return sub, err
+}
+
+
+// Make a call to the best healthy provider, failing over to the next on provider or unavailable state errors
+// If no providers are healthy, all providers are tried in order
+func (c *Client) call(fn func(client beacon.Client) error) error {
+
+    // Get providers to call in order
+    order := c.getCallOrder()
+
+    // Call providers
+    var err error
+    for _, ci := range order {
+        if err = fn(c.clients[ci]); err == nil {
+            return nil
+        }
+        if errors.Is(err, beacon.ErrStateUnavailable) {
+            continue
+        }
+        if !errors.Is(err, beacon.ErrProviderUnavailable) {
+            return err
+        }
+        c.setHealthy(ci, false)
+    }
+
+    // Return last error
+    if len(order) > 1 {
+        return fmt.Errorf("All beacon providers failed: %w", err)
+    }
+    return err
+
+}
+
+
+// Get the indices of providers to call in order; healthy providers first
+func (c *Client) getCallOrder() []int {
+
+    // Check provider health
+    c.checkHealth()
+
+    // Get healthy & unhealthy providers
+    c.lock.Lock()
+    defer c.lock.Unlock()
+    healthy := []int{}
+    unhealthy := []int{}
+    for ci := range c.clients {
+        if c.healthy[ci] {
+            healthy = append(healthy, ci)
+        } else {
+            unhealthy = append(unhealthy, ci)
+        }
+    }
+
+    // Return
+    return append(healthy, unhealthy...)
+
+}
+
+
+// Check provider health if the last check has expired
+func (c *Client) checkHealth() {
+
+    // Lock health checks
+    c.checkLock.Lock()
+    defer c.checkLock.Unlock()
+
+    // Check last health check time
+    c.lock.Lock()
+    checkedAt := c.checkedAt
+    c.lock.Unlock()
+    if time.Since(checkedAt) < HealthCheckInterval {
+        return
+    }
+
+    // Data
+    var wg sync.WaitGroup
+    synced := make([]bool, len(c.clients))
+    headEpochs := make([]uint64, len(c.clients))
+
+    // Get provider sync status & head
+    for ci, client := range c.clients {
+        wg.Add(1)
+        go func(ci int, client beacon.Client) {
+            defer wg.Done()
+            syncStatus, err := client.GetSyncStatus()
+            if err != nil || syncStatus.Syncing {
+                return
+            }
+            head, err := client.GetBeaconHead()
+            if err != nil {
+                return
+            }
+            synced[ci] = true
+            headEpochs[ci] = head.Epoch
+        }(ci, client)
+    }
+    wg.Wait()
+
+    // Get most advanced head epoch
+    var maxHeadEpoch uint64
+    for ci := range c.clients {
+        if synced[ci] && headEpochs[ci] > maxHeadEpoch {
+            maxHeadEpoch = headEpochs[ci]
+        }
+    }
+
+    // Update provider health
+    c.lock.Lock()
+    defer c.lock.Unlock()
+    for ci := range c.clients {
+        c.healthy[ci] = synced[ci] && headEpochs[ci] + MaxHeadDistance >= maxHeadEpoch
+    }
+    c.checkedAt = time.Now()
+
+}
+
+
+// Set a provider's health status
+func (c *Client) setHealthy(ci int, healthy bool) {
+    c.lock.Lock()
+    defer c.lock.Unlock()
+    c.healthy[ci] = healthy
+}
+
@@ -2,17 +2,21 @@ package nimbus
 
 import (
     "bytes"
+    "errors"
     "fmt"
     "strconv"
+    "strings"
     "time"
 
     "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/event"
     rpc "github.com/ethereum/go-ethereum/rpc"
     "github.com/rocket-pool/rocketpool-go/types"
     eth2types "github.com/wealdtech/go-eth2-types/v2"
     "golang.org/x/sync/errgroup"
 
     "github.com/rocket-pool/smartnode/shared/services/beacon"
+    "github.com/rocket-pool/smartnode/shared/services/beacon/standard"
     "github.com/rocket-pool/smartnode/shared/utils/eth2"
     hexutil "github.com/rocket-pool/smartnode/shared/utils/hex"
 )
@@ -26,25 +30,37 @@ const (
     RequestForkMethod                = "get_v1_beacon_states_fork"
     RequestValidatorsMethod          = "get_v1_beacon_states_stateId_validators"
     RequestVoluntaryExitMethod       = "post_v1_beacon_pool_voluntary_exits"
+    RequestAttesterDutiesMethod      = "get_v1_validator_duties_attester"
+    RequestProposerDutiesMethod      = "get_v1_validator_duties_proposer"
+    RequestBlockMethod               = "get_v1_beacon_blocks_blockId"
 
     MaxRequestValidatorsCount = 600 
 )
 
 // Nimbus client
+// Events are streamed from the standard REST API, which Nimbus serves separately from its RPC server
 type Client struct {
     client *rpc.Client
+    events *standard.Client
 }
 
 // Create new Nimbus client
-func NewClient(providerAddress string) (*Client, error) {
+// The REST address defaults to the RPC provider address if not set
+func NewClient(providerAddress string, restAddress string) (*Client, error) {
 
     // Start the RPC connection
     client, err := rpc.DialHTTP("http://" + providerAddress)
     if err != nil {
         return nil, fmt.Errorf("Could not connect to Nimbus RPC server: %s", err)
     }
+
+    // Initialize the event stream client
+    if restAddress == "" {
+        restAddress = providerAddress
+    }
     return &Client{
         client: client,
+        events: standard.NewClient(restAddress, standard.Quirks{ClientType: beacon.SingleProcess}),
     }, nil
 }
 
@@ -107,11 +123,22 @@ func (c *Client) GetEth2Config() (beacon.Eth2Config, error) {
         GenesisValidatorsRoot: genesis.GenesisValidatorsRoot,
         GenesisEpoch:          0,
         GenesisTime:           uint64(genesis.GenesisTime),
+        SecondsPerSlot:        uint64(eth2Config.SecondsPerSlot),
+        SlotsPerEpoch:         uint64(eth2Config.SlotsPerEpoch),
         SecondsPerEpoch:       uint64(eth2Config.SecondsPerSlot * eth2Config.SlotsPerEpoch),
     }, nil
 
 }
 
+// Get the genesis fork version from the genesis data
+func (c *Client) GetGenesisForkVersion() ([]byte, error) {
+    genesis, err := c.getGenesis()
+    if err != nil {
+        return []byte{}, err
+    }
+    return genesis.GenesisForkVersion, nil
+}
+
 // Get the beacon head
 func (c *Client) GetBeaconHead() (beacon.BeaconHead, error) {
 
@@ -215,6 +242,36 @@ func (c *Client) GetValidatorStatuses(pubkeys []types.ValidatorPubkey, opts *bea
 
 }
 
+// Get multiple validators' balances at a beacon state
+func (c *Client) GetValidatorBalances(pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorBalanceOptions) (map[types.ValidatorPubkey]uint64, error) {
+
+    // Get state ID
+    stateId := "head"
+    if opts != nil && opts.StateId != "" {
+        stateId = opts.StateId
+    } else if opts != nil {
+        var err error
+        stateId, err = c.getEpochStateId(opts.Epoch)
+        if err != nil {
+            return map[types.ValidatorPubkey]uint64{}, err
+        }
+    }
+
+    // Get validators
+    validators, err := c.getValidatorsByStateId(pubkeys, stateId)
+    if err != nil {
+        return map[types.ValidatorPubkey]uint64{}, err
+    }
+
+    // Build & return validator balance map
+    balances := make(map[types.ValidatorPubkey]uint64)
+    for _, validator := range validators {
+        balances[types.BytesToValidatorPubkey(validator.Validator.Pubkey)] = uint64(validator.Balance)
+    }
+    return balances, nil
+
+}
+
 // Get a validator's index
 func (c *Client) GetValidatorIndex(pubkey types.ValidatorPubkey) (uint64, error) {
 
@@ -233,6 +290,139 @@ func (c *Client) GetValidatorIndex(pubkey types.ValidatorPubkey) (uint64, error)
 
 }
 
+// Get multiple validators' balances at the start of each epoch in a range
+func (c *Client) GetValidatorBalanceHistory(pubkeys []types.ValidatorPubkey, startEpoch, endEpoch uint64) (map[types.ValidatorPubkey][]uint64, error) {
+    return beacon.GetBalanceHistory(c, pubkeys, startEpoch, endEpoch)
+}
+
+// Get multiple validators' attester duties for an epoch
+func (c *Client) GetAttesterDuties(pubkeys []types.ValidatorPubkey, epoch uint64) ([]beacon.AttesterDuty, error) {
+
+    // Get validator pubkeys
+    if len(pubkeys) == 0 {
+        return []beacon.AttesterDuty{}, nil
+    }
+    pubkeysHex := make([]string, len(pubkeys))
+    for ki, pubkey := range pubkeys {
+        pubkeysHex[ki] = hexutil.AddPrefix(pubkey.Hex())
+    }
+
+    // Get & return duties
+    response, err := c.getAttesterDuties(epoch, pubkeysHex)
+    if err != nil {
+        return []beacon.AttesterDuty{}, err
+    }
+    duties := make([]beacon.AttesterDuty, len(response))
+    for di, duty := range response {
+        duties[di] = beacon.AttesterDuty{
+            Pubkey:            types.BytesToValidatorPubkey(duty.Pubkey),
+            ValidatorIndex:    duty.ValidatorIndex,
+            Slot:              duty.Slot,
+            CommitteeIndex:    duty.CommitteeIndex,
+            CommitteePosition: duty.ValidatorCommitteeIndex,
+        }
+    }
+    return duties, nil
+
+}
+
+// Get multiple validators' proposer duties for an epoch
+func (c *Client) GetProposerDuties(pubkeys []types.ValidatorPubkey, epoch uint64) ([]beacon.ProposerDuty, error) {
+
+    // Get all proposer duties
+    response, err := c.getProposerDuties(epoch)
+    if err != nil {
+        return []beacon.ProposerDuty{}, err
+    }
+
+    // Filter duties by pubkeys & return
+    pubkeySet := make(map[types.ValidatorPubkey]bool, len(pubkeys))
+    for _, pubkey := range pubkeys {
+        pubkeySet[pubkey] = true
+    }
+    duties := []beacon.ProposerDuty{}
+    for _, duty := range response {
+        pubkey := types.BytesToValidatorPubkey(duty.Pubkey)
+        if !pubkeySet[pubkey] {
+            continue
+        }
+        duties = append(duties, beacon.ProposerDuty{
+            Pubkey:         pubkey,
+            ValidatorIndex: duty.ValidatorIndex,
+            Slot:           duty.Slot,
+        })
+    }
+    return duties, nil
+
+}
+
+// Get multiple validators' attestation & proposal performance for an epoch
+func (c *Client) GetValidatorPerformance(pubkeys []types.ValidatorPubkey, epoch uint64) (map[types.ValidatorPubkey]beacon.ValidatorPerformance, error) {
+
+    // Data
+    var wg errgroup.Group
+    var attesterDuties []beacon.AttesterDuty
+    var proposerDuties []beacon.ProposerDuty
+    var eth2Config Eth2ConfigResponse
+
+    // Get attester duties
+    wg.Go(func() error {
+        var err error
+        attesterDuties, err = c.GetAttesterDuties(pubkeys, epoch)
+        return err
+    })
+
+    // Get proposer duties
+    wg.Go(func() error {
+        var err error
+        proposerDuties, err = c.GetProposerDuties(pubkeys, epoch)
+        return err
+    })
+
+    // Get eth2 config
+    wg.Go(func() error {
+        var err error
+        eth2Config, err = c.getEth2Config()
+        return err
+    })
+
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return map[types.ValidatorPubkey]beacon.ValidatorPerformance{}, err
+    }
+
+    // Get blocks in the epoch & its attestation inclusion window
+    slotsPerEpoch := uint64(eth2Config.SlotsPerEpoch)
+    startSlot := epoch * slotsPerEpoch
+    summaries := []beacon.BlockSummary{}
+    for slot := startSlot; slot < startSlot+2*slotsPerEpoch; slot++ {
+        block, exists, err := c.getBlock(slot)
+        if err != nil {
+            return map[types.ValidatorPubkey]beacon.ValidatorPerformance{}, err
+        }
+        if !exists {
+            continue
+        }
+        summary := beacon.BlockSummary{
+            Slot:          block.Message.Slot,
+            ProposerIndex: block.Message.ProposerIndex,
+            Attestations:  make([]beacon.AttestationSummary, len(block.Message.Body.Attestations)),
+        }
+        for ai, attestation := range block.Message.Body.Attestations {
+            summary.Attestations[ai] = beacon.AttestationSummary{
+                Slot:            attestation.Data.Slot,
+                CommitteeIndex:  attestation.Data.Index,
+                AggregationBits: attestation.AggregationBits,
+            }
+        }
+        summaries = append(summaries, summary)
+    }
+
+    // Get & return performance
+    return beacon.GetPerformanceFromBlocks(attesterDuties, proposerDuties, summaries), nil
+
+}
+
 // Get domain data for a domain type at a given epoch
 func (c *Client) GetDomainData(domainType []byte, epoch uint64) ([]byte, error) {
 
@@ -286,12 +476,16 @@ func (c *Client) ExitValidator(validatorIndex, epoch uint64, signature types.Val
     })
 }
 
+// Subscribe to beacon chain events
+func (c *Client) SubscribeEvents(topics []string, events chan<- beacon.Event) (event.Subscription, error) {
+    return c.events.SubscribeEvents(topics, events)
+}
+
 // Get sync status
 func (c *Client) getSyncStatus() (bool, error) {
     var syncStatus bool
     if err := c.client.Call(&syncStatus, RequestSyncStatusMethod); err != nil {
-        message := c.getErrorString(err)
-        return false, fmt.Errorf("Could not get node sync status: %s", message)
+        return false, fmt.Errorf("Could not get node sync status: %w", c.getError(err))
     }
     return syncStatus, nil
 }
@@ -300,8 +494,7 @@ func (c *Client) getSyncStatus() (bool, error) {
 func (c *Client) getEth2Config() (Eth2ConfigResponse, error) {
     var eth2Config Eth2ConfigResponse
     if err := c.client.Call(&eth2Config, RequestEth2ConfigMethod); err != nil {
-        message := c.getErrorString(err)
-        return Eth2ConfigResponse{}, fmt.Errorf("Could not get eth2 config: %s", message)
+        return Eth2ConfigResponse{}, fmt.Errorf("Could not get eth2 config: %w", c.getError(err))
     }
     return eth2Config, nil
 }
@@ -310,8 +503,7 @@ func (c *Client) getEth2Config() (Eth2ConfigResponse, error) {
 func (c *Client) getGenesis() (GenesisResponse, error) {
     var genesis GenesisResponse
     if err := c.client.Call(&genesis, RequestGenesisMethod); err != nil {
-        message := c.getErrorString(err)
-        return GenesisResponse{}, fmt.Errorf("Could not get genesis data: %s", message)
+        return GenesisResponse{}, fmt.Errorf("Could not get genesis data: %w", c.getError(err))
     }
     return genesis, nil
 }
@@ -320,8 +512,7 @@ func (c *Client) getGenesis() (GenesisResponse, error) {
 func (c *Client) getFinalityCheckpoints(stateId string) (FinalityCheckpointsResponse, error) {
     var finalityCheckpoints FinalityCheckpointsResponse
     if err := c.client.Call(&finalityCheckpoints, RequestFinalityCheckpointsMethod, stateId); err != nil {
-        message := c.getErrorString(err)
-        return FinalityCheckpointsResponse{}, fmt.Errorf("Could not get finality checkpoints: %s", message)
+        return FinalityCheckpointsResponse{}, fmt.Errorf("Could not get finality checkpoints: %w", c.getError(err))
     }
     return finalityCheckpoints, nil
 }
@@ -330,8 +521,7 @@ func (c *Client) getFinalityCheckpoints(stateId string) (FinalityCheckpointsResp
 func (c *Client) getFork(stateId string) (ForkResponse, error) {
     var fork ForkResponse
     if err := c.client.Call(&fork, RequestForkMethod, stateId); err != nil {
-        message := c.getErrorString(err)
-        return ForkResponse{}, fmt.Errorf("Could not get fork data: %s", message)
+        return ForkResponse{}, fmt.Errorf("Could not get fork data: %w", c.getError(err))
     }
     return fork, nil
 }
@@ -341,31 +531,44 @@ func (c *Client) getValidators(stateId string, pubkeys []string) ([]Validator, e
     var validators []Validator
     if err := c.client.Call(&validators, RequestValidatorsMethod, stateId, pubkeys); err != nil {
         message := c.getErrorString(err)
-        return []Validator{}, fmt.Errorf("Could not get validators: %s", message)
+        if stateId != "head" && strings.Contains(strings.ToLower(message), "state not found") {
+            return []Validator{}, fmt.Errorf("Could not get validators at state %s: %w", stateId, beacon.ErrStateUnavailable)
+        }
+        return []Validator{}, fmt.Errorf("Could not get validators: %w", c.getError(err))
     }
     return validators, nil
 }
 
+// Get the state ID for the first slot of an epoch
+func (c *Client) getEpochStateId(epoch uint64) (string, error) {
+    eth2Config, err := c.getEth2Config()
+    if err != nil {
+        return "", err
+    }
+    slot := epoch * uint64(eth2Config.SlotsPerEpoch)
+    return strconv.FormatUint(slot, 10), nil
+}
+
 // Get validators by pubkeys and status options
 func (c *Client) getValidatorsByOpts(pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) ([]Validator, error) {
 
     // Get state ID
-    var stateId string
-    if opts == nil {
-        stateId = "head"
-    } else {
-
-        // Get eth2 config
-        eth2Config, err := c.getEth2Config()
+    stateId := "head"
+    if opts != nil {
+        var err error
+        stateId, err = c.getEpochStateId(opts.Epoch)
         if err != nil {
             return []Validator{}, err
         }
+    }
 
-        // Get slot nuimber
-        slot := opts.Epoch * uint64(eth2Config.SlotsPerEpoch)
-        stateId = strconv.FormatInt(int64(slot), 10)
+    // Get validators
+    return c.getValidatorsByStateId(pubkeys, stateId)
 
-    }
+}
+
+// Get validators by pubkeys at a beacon state
+func (c *Client) getValidatorsByStateId(pubkeys []types.ValidatorPubkey, stateId string) ([]Validator, error) {
 
     // Get validators
     if len(pubkeys) <= MaxRequestValidatorsCount {
@@ -408,15 +611,55 @@ func (c *Client) getValidatorsByOpts(pubkeys []types.ValidatorPubkey, opts *beac
 
 }
 
+// Get attester duties
+func (c *Client) getAttesterDuties(epoch uint64, pubkeys []string) ([]AttesterDuty, error) {
+    var duties []AttesterDuty
+    if err := c.client.Call(&duties, RequestAttesterDutiesMethod, epoch, pubkeys); err != nil {
+        return []AttesterDuty{}, fmt.Errorf("Could not get attester duties for epoch %d: %w", epoch, c.getError(err))
+    }
+    return duties, nil
+}
+
+// Get proposer duties
+func (c *Client) getProposerDuties(epoch uint64) ([]ProposerDuty, error) {
+    var duties []ProposerDuty
+    if err := c.client.Call(&duties, RequestProposerDutiesMethod, epoch); err != nil {
+        return []ProposerDuty{}, fmt.Errorf("Could not get proposer duties for epoch %d: %w", epoch, c.getError(err))
+    }
+    return duties, nil
+}
+
+// Get a block by slot; returns false if there is no block at the slot
+func (c *Client) getBlock(slot uint64) (Block, bool, error) {
+    var block Block
+    if err := c.client.Call(&block, RequestBlockMethod, strconv.FormatUint(slot, 10)); err != nil {
+        message := c.getErrorString(err)
+        if strings.Contains(strings.ToLower(message), "not found") {
+            return Block{}, false, nil
+        }
+        return Block{}, false, fmt.Errorf("Could not get block at slot %d: %w", slot, c.getError(err))
+    }
+    return block, true, nil
+}
+
 // Send voluntary exit request
 func (c *Client) postVoluntaryExit(request VoluntaryExitRequest) error {
     if err := c.client.Call(nil, RequestVoluntaryExitMethod, request); err != nil {
-        message := c.getErrorString(err)
-        return fmt.Errorf("Could not broadcast exit for validator at index %d: %s", request.Message.ValidatorIndex, message)
+        return fmt.Errorf("Could not broadcast exit for validator at index %d: %w", request.Message.ValidatorIndex, c.getError(err))
     }
     return nil
 }
 
+// Format an error from Nimbus
+// Errors which are not JSON error responses are returned as provider errors
+func (c *Client) getError(err error) error {
+    message := c.getErrorString(err)
+    if _, ok := err.(rpc.Error); ok {
+        return errors.New(message)
+    }
+    return fmt.Errorf("%w: %s", beacon.ErrProviderUnavailable, message)
+}
+
 // Format an error from Nimbus into a string
 func (c *Client) getErrorString(err error) string {
     var message string
@@ -60,6 +60,33 @@ type Validator struct {
         WithdrawableEpoch          int64     `json:"withdrawable_epoch"` // Same here
     } `json:"validator"`
 }
+type AttesterDuty struct {
+    Pubkey                  byteArray `json:"public_key"`
+    ValidatorIndex          uint64    `json:"validator_index"`
+    CommitteeIndex          uint64    `json:"committee_index"`
+    ValidatorCommitteeIndex uint64    `json:"validator_committee_index"`
+    Slot                    uint64    `json:"slot"`
+}
+type ProposerDuty struct {
+    Pubkey         byteArray `json:"public_key"`
+    ValidatorIndex uint64    `json:"validator_index"`
+    Slot           uint64    `json:"slot"`
+}
+type Block struct {
+    Message struct {
+        Slot          uint64 `json:"slot"`
+        ProposerIndex uint64 `json:"proposer_index"`
+        Body          struct {
+            Attestations []struct {
+                AggregationBits byteArray `json:"aggregation_bits"`
+                Data            struct {
+                    Slot  uint64 `json:"slot"`
+                    Index uint64 `json:"index"`
+                } `json:"data"`
+            } `json:"attestations"`
+        } `json:"body"`
+    } `json:"message"`
+}
 
 // Unsigned integer type
 type uinteger uint64
@@ -0,0 +1,107 @@
+package beacon
+
+import (
+    "github.com/rocket-pool/rocketpool-go/types"
+)
+
+
+// Beacon block summary used to determine validator performance
+type BlockSummary struct {
+    Slot uint64
+    ProposerIndex uint64
+    Attestations []AttestationSummary
+}
+type AttestationSummary struct {
+    Slot uint64
+    CommitteeIndex uint64
+    AggregationBits []byte
+}
+
+
+// Get validator balances at the start of each epoch in a range (inclusive) from a beacon client
+// Balances are indexed by epoch offset from the start epoch; validators with no balance at an epoch have a zero balance
+func GetBalanceHistory(bc Client, pubkeys []types.ValidatorPubkey, startEpoch, endEpoch uint64) (map[types.ValidatorPubkey][]uint64, error) {
+
+    // Initialize balance history
+    history := make(map[types.ValidatorPubkey][]uint64, len(pubkeys))
+    if endEpoch < startEpoch {
+        return history, nil
+    }
+    for _, pubkey := range pubkeys {
+        history[pubkey] = make([]uint64, endEpoch - startEpoch + 1)
+    }
+
+    // Get balances at each epoch
+    for epoch := startEpoch; epoch <= endEpoch; epoch++ {
+        balances, err := bc.GetValidatorBalances(pubkeys, &ValidatorBalanceOptions{Epoch: epoch})
+        if err != nil {
+            return map[types.ValidatorPubkey][]uint64{}, err
+        }
+        for pubkey, balance := range balances {
+            if _, ok := history[pubkey]; ok {
+                history[pubkey][epoch - startEpoch] = balance
+            }
+        }
+    }
+
+    // Return
+    return history, nil
+
+}
+
+
+// Get validator performance for an epoch from its duties and the blocks in its attestation inclusion window
+// Blocks must be ordered by slot; empty slots are omitted
+func GetPerformanceFromBlocks(attesterDuties []AttesterDuty, proposerDuties []ProposerDuty, blocks []BlockSummary) map[types.ValidatorPubkey]ValidatorPerformance {
+
+    // Initialize performance
+    performance := make(map[types.ValidatorPubkey]ValidatorPerformance)
+
+    // Check attestation duties
+    for _, duty := range attesterDuties {
+        vp := performance[duty.Pubkey]
+        vp.AttestationDuty = true
+        for _, block := range blocks {
+            if block.Slot <= duty.Slot { continue }
+            for _, attestation := range block.Attestations {
+                if attestation.Slot != duty.Slot || attestation.CommitteeIndex != duty.CommitteeIndex { continue }
+                if !getBit(attestation.AggregationBits, duty.CommitteePosition) { continue }
+                vp.AttestationIncluded = true
+                vp.InclusionDistance = block.Slot - duty.Slot
+                break
+            }
+            if vp.AttestationIncluded { break }
+        }
+        performance[duty.Pubkey] = vp
+    }
+
+    // Get proposers by slot
+    proposers := make(map[uint64]uint64, len(blocks))
+    for _, block := range blocks {
+        proposers[block.Slot] = block.ProposerIndex
+    }
+
+    // Check proposal duties
+    for _, duty := range proposerDuties {
+        vp := performance[duty.Pubkey]
+        vp.ProposalSlots = append(vp.ProposalSlots, duty.Slot)
+        if proposerIndex, ok := proposers[duty.Slot]; !ok || proposerIndex != duty.ValidatorIndex {
+            vp.MissedProposalSlots = append(vp.MissedProposalSlots, duty.Slot)
+        }
+        performance[duty.Pubkey] = vp
+    }
+
+    // Return
+    return performance
+
+}
+
+
+// Get a bit from an SSZ bitlist
+func getBit(bits []byte, index uint64) bool {
+    if index / 8 >= uint64(len(bits)) {
+        return false
+    }
+    return bits[index / 8] & (1 << (index % 8)) != 0
+}
+
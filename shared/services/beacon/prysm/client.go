@@ -1,14 +1,20 @@
 package prysm
 
 import (
+    "bytes"
     "context"
     "fmt"
+    "sort"
 
     "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/event"
     pbtypes "github.com/gogo/protobuf/types"
     pb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
     "github.com/rocket-pool/rocketpool-go/types"
+    "golang.org/x/sync/errgroup"
     "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
 
     "github.com/rocket-pool/smartnode/shared/services/beacon"
 )
@@ -27,7 +33,7 @@ type Client struct {
 func NewClient(providerAddress string) (*Client, error) {
 
     // Initialize gRPC connection
-    conn, err := grpc.Dial(providerAddress, grpc.WithInsecure(), grpc.WithBlock())
+    conn, err := grpc.Dial(providerAddress, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithUnaryInterceptor(unaryProviderErrorInterceptor), grpc.WithStreamInterceptor(streamProviderErrorInterceptor))
     if err != nil {
         return nil, fmt.Errorf("Could not connect to gRPC server: %w", err)
     }
@@ -109,12 +115,25 @@ func (c *Client) GetEth2Config() (beacon.Eth2Config, error) {
         GenesisValidatorsRoot: genesis.GenesisValidatorsRoot,
         GenesisEpoch: genesisEpoch,
         GenesisTime: uint64(genesis.GenesisTime.Seconds),
+        SecondsPerSlot: secondsPerSlot,
+        SlotsPerEpoch: slotsPerEpoch,
         SecondsPerEpoch: secondsPerSlot * slotsPerEpoch,
     }, nil
 
 }
 
 
+// Get the genesis fork version
+// Prysm's genesis data does not include the fork version, so it is read from the beacon chain config
+func (c *Client) GetGenesisForkVersion() ([]byte, error) {
+    config, err := c.bc.GetBeaconConfig(context.Background(), &pbtypes.Empty{})
+    if err != nil {
+        return []byte{}, fmt.Errorf("Could not get beacon chain config: %w", err)
+    }
+    return getConfigBytes(config.GetConfig(), "GenesisForkVersion")
+}
+
+
 // Get the beacon head
 func (c *Client) GetBeaconHead() (beacon.BeaconHead, error) {
 
@@ -285,6 +304,52 @@ func (c *Client) GetValidatorStatuses(pubkeys []types.ValidatorPubkey, opts *bea
 }
 
 
+// Get multiple validators' balances at a beacon state
+// Prysm only supports querying balances by epoch
+func (c *Client) GetValidatorBalances(pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorBalanceOptions) (map[types.ValidatorPubkey]uint64, error) {
+
+    // Check options
+    if opts != nil && opts.StateId != "" {
+        return map[types.ValidatorPubkey]uint64{}, fmt.Errorf("Could not get validator balances at state %s: Prysm only supports balance queries by epoch", opts.StateId)
+    }
+
+    // Build validator balances request
+    balancesRequest := &pb.ListValidatorBalancesRequest{
+        PublicKeys: make([][]byte, len(pubkeys)),
+    }
+    for ki, pubkey := range pubkeys {
+        balancesRequest.PublicKeys[ki] = pubkey.Bytes()
+    }
+    if opts != nil {
+        balancesRequest.QueryFilter = &pb.ListValidatorBalancesRequest_Epoch{Epoch: opts.Epoch}
+    }
+
+    // Load validator balances in pages
+    balances := make(map[types.ValidatorPubkey]uint64)
+    for {
+
+        // Get & add balances
+        response, err := c.bc.ListValidatorBalances(context.Background(), balancesRequest)
+        if err != nil {
+            if opts != nil && status.Code(err) == codes.NotFound {
+                return map[types.ValidatorPubkey]uint64{}, fmt.Errorf("Could not get validator balances at epoch %d: %w", opts.Epoch, beacon.ErrStateUnavailable)
+            }
+            return map[types.ValidatorPubkey]uint64{}, fmt.Errorf("Could not get validator balances: %w", err)
+        }
+        for _, balance := range response.Balances {
+            balances[types.BytesToValidatorPubkey(balance.PublicKey)] = balance.Balance
+        }
+
+        // Update request page token; break on last page
+        if response.NextPageToken == "" { break }
+        balancesRequest.PageToken = response.NextPageToken
+
+    }
+    return balances, nil
+
+}
+
+
 // Get a validator's index
 func (c *Client) GetValidatorIndex(pubkey types.ValidatorPubkey) (uint64, error) {
     validatorIndex, err := c.vc.ValidatorIndex(context.Background(), &pb.ValidatorIndexRequest{PublicKey: pubkey.Bytes()})
@@ -295,6 +360,116 @@ func (c *Client) GetValidatorIndex(pubkey types.ValidatorPubkey) (uint64, error)
 }
 
 
+// Get multiple validators' balances at the start of each epoch in a range
+func (c *Client) GetValidatorBalanceHistory(pubkeys []types.ValidatorPubkey, startEpoch, endEpoch uint64) (map[types.ValidatorPubkey][]uint64, error) {
+    return beacon.GetBalanceHistory(c, pubkeys, startEpoch, endEpoch)
+}
+
+
+// Get multiple validators' attester duties for an epoch
+func (c *Client) GetAttesterDuties(pubkeys []types.ValidatorPubkey, epoch uint64) ([]beacon.AttesterDuty, error) {
+
+    // Get validator assignments
+    assignments, err := c.listValidatorAssignments(pubkeys, epoch)
+    if err != nil {
+        return []beacon.AttesterDuty{}, err
+    }
+
+    // Build & return duties
+    duties := make([]beacon.AttesterDuty, 0, len(assignments))
+    for _, assignment := range assignments {
+        var position uint64
+        for ci, validatorIndex := range assignment.BeaconCommittees {
+            if validatorIndex == assignment.ValidatorIndex {
+                position = uint64(ci)
+                break
+            }
+        }
+        duties = append(duties, beacon.AttesterDuty{
+            Pubkey: types.BytesToValidatorPubkey(assignment.PublicKey),
+            ValidatorIndex: assignment.ValidatorIndex,
+            Slot: assignment.AttesterSlot,
+            CommitteeIndex: assignment.CommitteeIndex,
+            CommitteePosition: position,
+        })
+    }
+    return duties, nil
+
+}
+
+
+// Get multiple validators' proposer duties for an epoch
+func (c *Client) GetProposerDuties(pubkeys []types.ValidatorPubkey, epoch uint64) ([]beacon.ProposerDuty, error) {
+
+    // Get validator assignments
+    assignments, err := c.listValidatorAssignments(pubkeys, epoch)
+    if err != nil {
+        return []beacon.ProposerDuty{}, err
+    }
+
+    // Build & return duties
+    duties := []beacon.ProposerDuty{}
+    for _, assignment := range assignments {
+        for _, slot := range assignment.ProposerSlots {
+            duties = append(duties, beacon.ProposerDuty{
+                Pubkey: types.BytesToValidatorPubkey(assignment.PublicKey),
+                ValidatorIndex: assignment.ValidatorIndex,
+                Slot: slot,
+            })
+        }
+    }
+    return duties, nil
+
+}
+
+
+// Get multiple validators' attestation & proposal performance for an epoch
+func (c *Client) GetValidatorPerformance(pubkeys []types.ValidatorPubkey, epoch uint64) (map[types.ValidatorPubkey]beacon.ValidatorPerformance, error) {
+
+    // Data
+    var wg errgroup.Group
+    var attesterDuties []beacon.AttesterDuty
+    var proposerDuties []beacon.ProposerDuty
+    var blocks []beacon.BlockSummary
+    var nextBlocks []beacon.BlockSummary
+
+    // Get attester duties
+    wg.Go(func() error {
+        var err error
+        attesterDuties, err = c.GetAttesterDuties(pubkeys, epoch)
+        return err
+    })
+
+    // Get proposer duties
+    wg.Go(func() error {
+        var err error
+        proposerDuties, err = c.GetProposerDuties(pubkeys, epoch)
+        return err
+    })
+
+    // Get blocks in the epoch & its attestation inclusion window
+    wg.Go(func() error {
+        var err error
+        blocks, err = c.listBlocks(epoch)
+        return err
+    })
+    wg.Go(func() error {
+        var err error
+        nextBlocks, err = c.listBlocks(epoch + 1)
+        return err
+    })
+
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return map[types.ValidatorPubkey]beacon.ValidatorPerformance{}, err
+    }
+
+    // Get & return performance
+    return beacon.GetPerformanceFromBlocks(attesterDuties, proposerDuties, append(blocks, nextBlocks...)), nil
+
+}
+
+
 // Get domain data for a domain type at a given epoch
 func (c *Client) GetDomainData(domainType []byte, epoch uint64) ([]byte, error) {
     domainData, err := c.vc.DomainData(context.Background(), &pb.DomainRequest{Domain: domainType, Epoch: epoch})
@@ -328,3 +503,247 @@ func (c *Client) ExitValidator(validatorIndex, epoch uint64, signature types.Val
 
 }
 
+
+// Subscribe to beacon chain events
+// Prysm does not serve the standard event stream, so events are derived from its chain head & block streams
+// Voluntary exits are reported once included in a block rather than on receipt
+func (c *Client) SubscribeEvents(topics []string, events chan<- beacon.Event) (event.Subscription, error) {
+
+    // Get requested topics
+    requested := make(map[string]bool)
+    for _, topic := range topics {
+        requested[topic] = true
+    }
+
+    // Open streams
+    ctx, cancel := context.WithCancel(context.Background())
+    var headStream pb.BeaconChain_StreamChainHeadClient
+    var blockStream pb.BeaconChain_StreamBlocksClient
+    if requested[beacon.EventTopicHead] || requested[beacon.EventTopicFinalizedCheckpoint] || requested[beacon.EventTopicChainReorg] {
+        var err error
+        headStream, err = c.bc.StreamChainHead(ctx, &pbtypes.Empty{})
+        if err != nil {
+            cancel()
+            return nil, fmt.Errorf("Could not subscribe to beacon chain head: %w", err)
+        }
+    }
+    if requested[beacon.EventTopicVoluntaryExit] {
+        var err error
+        blockStream, err = c.bc.StreamBlocks(ctx, &pbtypes.Empty{})
+        if err != nil {
+            cancel()
+            return nil, fmt.Errorf("Could not subscribe to beacon blocks: %w", err)
+        }
+    }
+
+    // Dispatch events until unsubscribed
+    return event.NewSubscription(func(quit <-chan struct{}) error {
+        defer cancel()
+        errs := make(chan error, 2)
+        if headStream != nil {
+            go func() { errs <- c.readChainHeadEvents(headStream, requested, events, quit) }()
+        }
+        if blockStream != nil {
+            go func() { errs <- c.readBlockEvents(blockStream, events, quit) }()
+        }
+        select {
+            case <-quit:
+                return nil
+            case err := <-errs:
+                return err
+        }
+    }), nil
+
+}
+
+
+// Read chain head updates and dispatch head, finality & reorg events
+// A reorg is reported when the head moves to a different block at or before the previous head slot
+func (c *Client) readChainHeadEvents(stream pb.BeaconChain_StreamChainHeadClient, requested map[string]bool, events chan<- beacon.Event, quit <-chan struct{}) error {
+    var last *pb.ChainHead
+    for {
+
+        // Get chain head
+        head, err := stream.Recv()
+        if err != nil {
+            return fmt.Errorf("Could not read beacon chain head stream: %w", err)
+        }
+
+        // Get events
+        headEvents := []beacon.Event{}
+        headChanged := (last == nil || !bytes.Equal(head.HeadBlockRoot, last.HeadBlockRoot))
+        if requested[beacon.EventTopicChainReorg] && last != nil && headChanged && head.HeadSlot <= last.HeadSlot {
+            headEvents = append(headEvents, beacon.Event{
+                Topic: beacon.EventTopicChainReorg,
+                Slot: head.HeadSlot,
+                Epoch: head.HeadEpoch,
+                BlockRoot: common.BytesToHash(head.HeadBlockRoot),
+                Depth: last.HeadSlot - head.HeadSlot + 1,
+            })
+        }
+        if requested[beacon.EventTopicHead] && headChanged {
+            headEvents = append(headEvents, beacon.Event{
+                Topic: beacon.EventTopicHead,
+                Slot: head.HeadSlot,
+                BlockRoot: common.BytesToHash(head.HeadBlockRoot),
+            })
+        }
+        if requested[beacon.EventTopicFinalizedCheckpoint] && last != nil && head.FinalizedEpoch > last.FinalizedEpoch {
+            headEvents = append(headEvents, beacon.Event{
+                Topic: beacon.EventTopicFinalizedCheckpoint,
+                Epoch: head.FinalizedEpoch,
+                BlockRoot: common.BytesToHash(head.FinalizedBlockRoot),
+            })
+        }
+        last = head
+
+        // Dispatch events
+        for _, e := range headEvents {
+            select {
+                case events <- e:
+                case <-quit:
+                    return nil
+            }
+        }
+
+    }
+}
+
+
+// Read blocks and dispatch voluntary exit events
+func (c *Client) readBlockEvents(stream pb.BeaconChain_StreamBlocksClient, events chan<- beacon.Event, quit <-chan struct{}) error {
+    for {
+
+        // Get block
+        block, err := stream.Recv()
+        if err != nil {
+            return fmt.Errorf("Could not read beacon block stream: %w", err)
+        }
+        if block.Block == nil || block.Block.Body == nil { continue }
+
+        // Dispatch exit events
+        for _, exit := range block.Block.Body.VoluntaryExits {
+            if exit.Exit == nil { continue }
+            select {
+                case events <- beacon.Event{
+                    Topic: beacon.EventTopicVoluntaryExit,
+                    Epoch: exit.Exit.Epoch,
+                    ValidatorIndex: exit.Exit.ValidatorIndex,
+                }:
+                case <-quit:
+                    return nil
+            }
+        }
+
+    }
+}
+
+
+// Get validator assignments for an epoch
+func (c *Client) listValidatorAssignments(pubkeys []types.ValidatorPubkey, epoch uint64) ([]*pb.ValidatorAssignments_CommitteeAssignment, error) {
+
+    // Build validator assignments request
+    if len(pubkeys) == 0 {
+        return []*pb.ValidatorAssignments_CommitteeAssignment{}, nil
+    }
+    assignmentsRequest := &pb.ListValidatorAssignmentsRequest{
+        QueryFilter: &pb.ListValidatorAssignmentsRequest_Epoch{Epoch: epoch},
+        PublicKeys: make([][]byte, len(pubkeys)),
+    }
+    for ki, pubkey := range pubkeys {
+        assignmentsRequest.PublicKeys[ki] = pubkey.Bytes()
+    }
+
+    // Load validator assignments in pages
+    assignments := make([]*pb.ValidatorAssignments_CommitteeAssignment, 0, len(pubkeys))
+    for {
+
+        // Get & add assignments
+        response, err := c.bc.ListValidatorAssignments(context.Background(), assignmentsRequest)
+        if err != nil {
+            return []*pb.ValidatorAssignments_CommitteeAssignment{}, fmt.Errorf("Could not get validator assignments for epoch %d: %w", epoch, err)
+        }
+        assignments = append(assignments, response.Assignments...)
+
+        // Update request page token; break on last page
+        if response.NextPageToken == "" { break }
+        assignmentsRequest.PageToken = response.NextPageToken
+
+    }
+    return assignments, nil
+
+}
+
+
+// Get block summaries for an epoch, ordered by slot
+func (c *Client) listBlocks(epoch uint64) ([]beacon.BlockSummary, error) {
+
+    // Build blocks request
+    blocksRequest := &pb.ListBlocksRequest{
+        QueryFilter: &pb.ListBlocksRequest_Epoch{Epoch: epoch},
+    }
+
+    // Load blocks in pages
+    blocks := []beacon.BlockSummary{}
+    for {
+
+        // Get & add blocks
+        response, err := c.bc.ListBlocks(context.Background(), blocksRequest)
+        if err != nil {
+            return []beacon.BlockSummary{}, fmt.Errorf("Could not get blocks for epoch %d: %w", epoch, err)
+        }
+        for _, container := range response.BlockContainers {
+            if container.Block == nil || container.Block.Block == nil { continue }
+            block := container.Block.Block
+            summary := beacon.BlockSummary{
+                Slot: block.Slot,
+                ProposerIndex: block.ProposerIndex,
+            }
+            if block.Body != nil {
+                for _, attestation := range block.Body.Attestations {
+                    if attestation.Data == nil { continue }
+                    summary.Attestations = append(summary.Attestations, beacon.AttestationSummary{
+                        Slot: attestation.Data.Slot,
+                        CommitteeIndex: attestation.Data.CommitteeIndex,
+                        AggregationBits: attestation.AggregationBits,
+                    })
+                }
+            }
+            blocks = append(blocks, summary)
+        }
+
+        // Update request page token; break on last page
+        if response.NextPageToken == "" { break }
+        blocksRequest.PageToken = response.NextPageToken
+
+    }
+
+    // Sort by slot & return
+    sort.Slice(blocks, func(i, j int) bool { return blocks[i].Slot < blocks[j].Slot })
+    return blocks, nil
+
+}
+
+
+// Mark unary call errors caused by the beacon node being unavailable as provider errors
+func unaryProviderErrorInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+    return getProviderError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+
+// Mark stream errors caused by the beacon node being unavailable as provider errors
+func streamProviderErrorInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+    stream, err := streamer(ctx, desc, cc, method, opts...)
+    return stream, getProviderError(err)
+}
+
+
+// Wrap a gRPC error as a provider error if it was caused by the beacon node rather than the request
+func getProviderError(err error) error {
+    switch status.Code(err) {
+        case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.ResourceExhausted:
+            return fmt.Errorf("%w: %s", beacon.ErrProviderUnavailable, err.Error())
+    }
+    return err
+}
+
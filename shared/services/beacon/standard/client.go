@@ -0,0 +1,998 @@
+package standard
+
+import (
+    "bufio"
+    "bytes"
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "io/ioutil"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/ethereum/go-ethereum/event"
+    "github.com/rocket-pool/rocketpool-go/types"
+    eth2types "github.com/wealdtech/go-eth2-types/v2"
+    "golang.org/x/sync/errgroup"
+
+    "github.com/rocket-pool/smartnode/shared/services/beacon"
+    "github.com/rocket-pool/smartnode/shared/utils/eth2"
+    hexutil "github.com/rocket-pool/smartnode/shared/utils/hex"
+)
+
+
+// Config
+const (
+    RequestUrlFormat = "%s://%s%s"
+    RequestProtocol = "http"
+    RequestContentType = "application/json"
+    RequestEventStreamContentType = "text/event-stream"
+
+    RequestSyncStatusPath = "/eth/v1/node/syncing"
+    RequestEth2ConfigPath = "/eth/v1/config/spec"
+    RequestGenesisPath = "/eth/v1/beacon/genesis"
+    RequestFinalityCheckpointsPath = "/eth/v1/beacon/states/%s/finality_checkpoints"
+    RequestForkPath = "/eth/v1/beacon/states/%s/fork"
+    RequestValidatorsPath = "/eth/v1/beacon/states/%s/validators"
+    RequestVoluntaryExitPath = "/eth/v1/beacon/pool/voluntary_exits"
+    RequestAttesterDutiesPath = "/eth/v1/validator/duties/attester/%d"
+    RequestProposerDutiesPath = "/eth/v1/validator/duties/proposer/%d"
+    RequestBlockPath = "/eth/v1/beacon/blocks/%d"
+    RequestEventsPath = "/eth/v1/events?topics=%s"
+
+    MaxRequestValidatorsCount = 600
+)
+
+
+// Client quirks
+// Deviations from the standard Beacon API which some clients require
+type Quirks struct {
+    SyncDistanceSyncStatus bool     // Sync status is determined by sync distance rather than the is_syncing flag
+    FilterNullPubkeys bool          // Null pubkeys are rejected and must not be requested
+    FetchAllValidators bool         // Validator sets larger than the maximum request size are fetched in full & filtered rather than in batches
+    ClientType beacon.BeaconClientType // Whether the beacon & validator clients run in separate processes
+}
+
+
+// Standard Beacon API client
+type Client struct {
+    providerAddress string
+    quirks Quirks
+}
+
+
+// Create new standard Beacon API client
+func NewClient(providerAddress string, quirks Quirks) *Client {
+    return &Client{
+        providerAddress: providerAddress,
+        quirks: quirks,
+    }
+}
+
+
+// Close the client connection
+func (c *Client) Close() {}
+
+
+// Get the beacon client type
+func (c *Client) GetClientType() (beacon.BeaconClientType) {
+    return c.quirks.ClientType
+}
+
+
+// Get the node's sync status
+func (c *Client) GetSyncStatus() (beacon.SyncStatus, error) {
+
+    // Get sync status
+    syncStatus, err := c.getSyncStatus()
+    if err != nil {
+        return beacon.SyncStatus{}, err
+    }
+
+    // Get syncing status
+    isSyncing := syncStatus.Data.IsSyncing
+    if c.quirks.SyncDistanceSyncStatus {
+        isSyncing = (syncStatus.Data.SyncDistance != 0)
+    }
+
+    // Return response
+    return beacon.SyncStatus{
+        Syncing: isSyncing,
+    }, nil
+
+}
+
+
+// Get the eth2 config
+func (c *Client) GetEth2Config() (beacon.Eth2Config, error) {
+
+    // Data
+    var wg errgroup.Group
+    var eth2Config Eth2ConfigResponse
+    var genesis GenesisResponse
+
+    // Get eth2 config
+    wg.Go(func() error {
+        var err error
+        eth2Config, err = c.getEth2Config()
+        return err
+    })
+
+    // Get genesis
+    wg.Go(func() error {
+        var err error
+        genesis, err = c.getGenesis()
+        return err
+    })
+    
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return beacon.Eth2Config{}, err
+    }
+
+    // Return response
+    return beacon.Eth2Config{
+        GenesisForkVersion: genesis.Data.GenesisForkVersion,
+        GenesisValidatorsRoot: genesis.Data.GenesisValidatorsRoot,
+        GenesisEpoch: 0,
+        GenesisTime: uint64(genesis.Data.GenesisTime),
+        SecondsPerSlot: uint64(eth2Config.Data.SecondsPerSlot),
+        SlotsPerEpoch: uint64(eth2Config.Data.SlotsPerEpoch),
+        SecondsPerEpoch: uint64(eth2Config.Data.SecondsPerSlot * eth2Config.Data.SlotsPerEpoch),
+    }, nil
+
+}
+
+
+// Get the genesis fork version from the genesis data
+func (c *Client) GetGenesisForkVersion() ([]byte, error) {
+    genesis, err := c.getGenesis()
+    if err != nil {
+        return []byte{}, err
+    }
+    return genesis.Data.GenesisForkVersion, nil
+}
+
+
+// Get the beacon head
+func (c *Client) GetBeaconHead() (beacon.BeaconHead, error) {
+
+    // Data
+    var wg errgroup.Group
+    var eth2Config beacon.Eth2Config
+    var finalityCheckpoints FinalityCheckpointsResponse
+
+    // Get eth2 config
+    wg.Go(func() error {
+        var err error
+        eth2Config, err = c.GetEth2Config()
+        return err
+    })
+
+    // Get finality checkpoints
+    wg.Go(func() error {
+        var err error
+        finalityCheckpoints, err = c.getFinalityCheckpoints("head")
+        return err
+    })
+    
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return beacon.BeaconHead{}, err
+    }
+
+    // Return response
+    return beacon.BeaconHead{
+        Epoch: eth2.EpochAt(eth2Config, uint64(time.Now().Unix())),
+        FinalizedEpoch: uint64(finalityCheckpoints.Data.Finalized.Epoch),
+        JustifiedEpoch: uint64(finalityCheckpoints.Data.CurrentJustified.Epoch),
+        PreviousJustifiedEpoch: uint64(finalityCheckpoints.Data.PreviousJustified.Epoch),
+    }, nil
+
+}
+
+
+// Get a validator's status
+func (c *Client) GetValidatorStatus(pubkey types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (beacon.ValidatorStatus, error) {
+
+    // Return zero status for null pubkey
+    if c.quirks.FilterNullPubkeys && pubkey == (types.ValidatorPubkey{}) {
+        return beacon.ValidatorStatus{}, nil
+    }
+
+    // Get validator
+    validators, err := c.getValidatorsByOpts([]types.ValidatorPubkey{pubkey}, opts)
+    if err != nil {
+        return beacon.ValidatorStatus{}, err
+    }
+    if len(validators.Data) == 0 {
+        return beacon.ValidatorStatus{}, nil
+    }
+    validator := validators.Data[0]
+
+    // Return response
+    return beacon.ValidatorStatus{
+        Pubkey: types.BytesToValidatorPubkey(validator.Validator.Pubkey),
+        WithdrawalCredentials: common.BytesToHash(validator.Validator.WithdrawalCredentials),
+        Balance: uint64(validator.Balance),
+        EffectiveBalance: uint64(validator.Validator.EffectiveBalance),
+        Slashed: validator.Validator.Slashed,
+        ActivationEligibilityEpoch: uint64(validator.Validator.ActivationEligibilityEpoch),
+        ActivationEpoch: uint64(validator.Validator.ActivationEpoch),
+        ExitEpoch: uint64(validator.Validator.ExitEpoch),
+        WithdrawableEpoch: uint64(validator.Validator.WithdrawableEpoch),
+        Exists: true,
+    }, nil
+
+}
+
+
+// Get multiple validators' statuses
+func (c *Client) GetValidatorStatuses(pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (map[types.ValidatorPubkey]beacon.ValidatorStatus, error) {
+
+    // Get validators
+    validators, err := c.getValidatorsByOpts(c.filterPubkeys(pubkeys), opts)
+    if err != nil {
+        return map[types.ValidatorPubkey]beacon.ValidatorStatus{}, err
+    }
+
+    // Build validator status map
+    statuses := make(map[types.ValidatorPubkey]beacon.ValidatorStatus)
+    for _, validator := range validators.Data {
+
+        // Get validator pubkey
+        pubkey := types.BytesToValidatorPubkey(validator.Validator.Pubkey)
+
+        // Add status
+        statuses[pubkey] = beacon.ValidatorStatus{
+            Pubkey: types.BytesToValidatorPubkey(validator.Validator.Pubkey),
+            WithdrawalCredentials: common.BytesToHash(validator.Validator.WithdrawalCredentials),
+            Balance: uint64(validator.Balance),
+            EffectiveBalance: uint64(validator.Validator.EffectiveBalance),
+            Slashed: validator.Validator.Slashed,
+            ActivationEligibilityEpoch: uint64(validator.Validator.ActivationEligibilityEpoch),
+            ActivationEpoch: uint64(validator.Validator.ActivationEpoch),
+            ExitEpoch: uint64(validator.Validator.ExitEpoch),
+            WithdrawableEpoch: uint64(validator.Validator.WithdrawableEpoch),
+            Exists: true,
+        }
+
+    }
+
+    // Add zero status for filtered null pubkey if requested
+    if len(c.filterPubkeys(pubkeys)) < len(pubkeys) {
+        statuses[types.ValidatorPubkey{}] = beacon.ValidatorStatus{}
+    }
+
+    // Return
+    return statuses, nil
+
+}
+
+
+// Get multiple validators' balances at a beacon state
+func (c *Client) GetValidatorBalances(pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorBalanceOptions) (map[types.ValidatorPubkey]uint64, error) {
+
+    // Get state ID
+    stateId := "head"
+    if opts != nil && opts.StateId != "" {
+        stateId = opts.StateId
+    } else if opts != nil {
+        var err error
+        stateId, err = c.getEpochStateId(opts.Epoch)
+        if err != nil {
+            return map[types.ValidatorPubkey]uint64{}, err
+        }
+    }
+
+    // Get validators
+    validators, err := c.getValidatorsByStateId(c.filterPubkeys(pubkeys), stateId)
+    if err != nil {
+        return map[types.ValidatorPubkey]uint64{}, err
+    }
+
+    // Build & return validator balance map
+    balances := make(map[types.ValidatorPubkey]uint64)
+    for _, validator := range validators.Data {
+        balances[types.BytesToValidatorPubkey(validator.Validator.Pubkey)] = uint64(validator.Balance)
+    }
+    return balances, nil
+
+}
+
+
+// Get a validator's index
+func (c *Client) GetValidatorIndex(pubkey types.ValidatorPubkey) (uint64, error) {
+
+    // Get validator
+    validators, err := c.getValidatorsByOpts([]types.ValidatorPubkey{pubkey}, nil)
+    if err != nil {
+        return 0, err
+    }
+    if len(validators.Data) == 0 {
+        return 0, fmt.Errorf("Validator %s index not found.", pubkey.Hex())
+    }
+    validator := validators.Data[0]
+
+    // Return validator index
+    return uint64(validator.Index), nil
+
+}
+
+
+// Get multiple validators' balances at the start of each epoch in a range
+func (c *Client) GetValidatorBalanceHistory(pubkeys []types.ValidatorPubkey, startEpoch, endEpoch uint64) (map[types.ValidatorPubkey][]uint64, error) {
+    return beacon.GetBalanceHistory(c, pubkeys, startEpoch, endEpoch)
+}
+
+
+// Get multiple validators' attester duties for an epoch
+func (c *Client) GetAttesterDuties(pubkeys []types.ValidatorPubkey, epoch uint64) ([]beacon.AttesterDuty, error) {
+
+    // Get validator indices
+    validators, err := c.getValidatorsByOpts(c.filterPubkeys(pubkeys), nil)
+    if err != nil {
+        return []beacon.AttesterDuty{}, err
+    }
+    if len(validators.Data) == 0 {
+        return []beacon.AttesterDuty{}, nil
+    }
+    indices := make([]uinteger, len(validators.Data))
+    for vi, validator := range validators.Data {
+        indices[vi] = validator.Index
+    }
+
+    // Get & return duties
+    response, err := c.postAttesterDuties(epoch, indices)
+    if err != nil {
+        return []beacon.AttesterDuty{}, err
+    }
+    duties := make([]beacon.AttesterDuty, len(response.Data))
+    for di, duty := range response.Data {
+        duties[di] = beacon.AttesterDuty{
+            Pubkey: types.BytesToValidatorPubkey(duty.Pubkey),
+            ValidatorIndex: uint64(duty.ValidatorIndex),
+            Slot: uint64(duty.Slot),
+            CommitteeIndex: uint64(duty.CommitteeIndex),
+            CommitteePosition: uint64(duty.ValidatorCommitteeIndex),
+        }
+    }
+    return duties, nil
+
+}
+
+
+// Get multiple validators' proposer duties for an epoch
+func (c *Client) GetProposerDuties(pubkeys []types.ValidatorPubkey, epoch uint64) ([]beacon.ProposerDuty, error) {
+
+    // Get all proposer duties
+    response, err := c.getProposerDuties(epoch)
+    if err != nil {
+        return []beacon.ProposerDuty{}, err
+    }
+
+    // Filter duties by pubkeys & return
+    pubkeySet := make(map[types.ValidatorPubkey]bool, len(pubkeys))
+    for _, pubkey := range pubkeys {
+        pubkeySet[pubkey] = true
+    }
+    duties := []beacon.ProposerDuty{}
+    for _, duty := range response.Data {
+        pubkey := types.BytesToValidatorPubkey(duty.Pubkey)
+        if !pubkeySet[pubkey] { continue }
+        duties = append(duties, beacon.ProposerDuty{
+            Pubkey: pubkey,
+            ValidatorIndex: uint64(duty.ValidatorIndex),
+            Slot: uint64(duty.Slot),
+        })
+    }
+    return duties, nil
+
+}
+
+
+// Get multiple validators' attestation & proposal performance for an epoch
+func (c *Client) GetValidatorPerformance(pubkeys []types.ValidatorPubkey, epoch uint64) (map[types.ValidatorPubkey]beacon.ValidatorPerformance, error) {
+
+    // Data
+    var wg errgroup.Group
+    var attesterDuties []beacon.AttesterDuty
+    var proposerDuties []beacon.ProposerDuty
+    var eth2Config Eth2ConfigResponse
+
+    // Get attester duties
+    wg.Go(func() error {
+        var err error
+        attesterDuties, err = c.GetAttesterDuties(pubkeys, epoch)
+        return err
+    })
+
+    // Get proposer duties
+    wg.Go(func() error {
+        var err error
+        proposerDuties, err = c.GetProposerDuties(pubkeys, epoch)
+        return err
+    })
+
+    // Get eth2 config
+    wg.Go(func() error {
+        var err error
+        eth2Config, err = c.getEth2Config()
+        return err
+    })
+
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return map[types.ValidatorPubkey]beacon.ValidatorPerformance{}, err
+    }
+
+    // Get blocks in the epoch & its attestation inclusion window
+    slotsPerEpoch := uint64(eth2Config.Data.SlotsPerEpoch)
+    startSlot := epoch * slotsPerEpoch
+    blocks := make([]*beacon.BlockSummary, 2 * slotsPerEpoch)
+    var bwg errgroup.Group
+    for bi := range blocks {
+        bi := bi
+        bwg.Go(func() error {
+            block, exists, err := c.getBlock(startSlot + uint64(bi))
+            if err != nil || !exists {
+                return err
+            }
+            summary := &beacon.BlockSummary{
+                Slot: uint64(block.Data.Message.Slot),
+                ProposerIndex: uint64(block.Data.Message.ProposerIndex),
+                Attestations: make([]beacon.AttestationSummary, len(block.Data.Message.Body.Attestations)),
+            }
+            for ai, attestation := range block.Data.Message.Body.Attestations {
+                summary.Attestations[ai] = beacon.AttestationSummary{
+                    Slot: uint64(attestation.Data.Slot),
+                    CommitteeIndex: uint64(attestation.Data.Index),
+                    AggregationBits: attestation.AggregationBits,
+                }
+            }
+            blocks[bi] = summary
+            return nil
+        })
+    }
+    if err := bwg.Wait(); err != nil {
+        return map[types.ValidatorPubkey]beacon.ValidatorPerformance{}, err
+    }
+    summaries := []beacon.BlockSummary{}
+    for _, block := range blocks {
+        if block != nil {
+            summaries = append(summaries, *block)
+        }
+    }
+
+    // Get & return performance
+    return beacon.GetPerformanceFromBlocks(attesterDuties, proposerDuties, summaries), nil
+
+}
+
+
+// Get domain data for a domain type at a given epoch
+func (c *Client) GetDomainData(domainType []byte, epoch uint64) ([]byte, error) {
+
+    // Data
+    var wg errgroup.Group
+    var genesis GenesisResponse
+    var fork ForkResponse
+
+    // Get genesis
+    wg.Go(func() error {
+        var err error
+        genesis, err = c.getGenesis()
+        return err
+    })
+
+    // Get fork
+    wg.Go(func() error {
+        var err error
+        fork, err = c.getFork("head")
+        return err
+    })
+    
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return []byte{}, err
+    }
+
+    // Get fork version
+    var forkVersion []byte
+    if epoch < uint64(fork.Data.Epoch) {
+        forkVersion = fork.Data.PreviousVersion
+    } else {
+        forkVersion = fork.Data.CurrentVersion
+    }
+
+    // Compute & return domain
+    var dt [4]byte
+    copy(dt[:], domainType[:])
+    return eth2types.Domain(dt, forkVersion, genesis.Data.GenesisValidatorsRoot), nil
+
+}
+
+
+// Perform a voluntary exit on a validator
+func (c *Client) ExitValidator(validatorIndex, epoch uint64, signature types.ValidatorSignature) error {
+    return c.postVoluntaryExit(VoluntaryExitRequest{
+        Message: VoluntaryExitMessage{
+            Epoch: uinteger(epoch),
+            ValidatorIndex: uinteger(validatorIndex),
+        },
+        Signature: signature.Bytes(),
+    })
+}
+
+
+// Subscribe to beacon chain events
+// Events are read from the server-sent event stream until unsubscribed or the stream fails
+func (c *Client) SubscribeEvents(topics []string, events chan<- beacon.Event) (event.Subscription, error) {
+
+    // Open event stream
+    ctx, cancel := context.WithCancel(context.Background())
+    response, err := c.getEventStream(ctx, topics)
+    if err != nil {
+        cancel()
+        return nil, err
+    }
+
+    // Dispatch events until unsubscribed
+    return event.NewSubscription(func(quit <-chan struct{}) error {
+        defer response.Body.Close()
+        defer cancel()
+        go func() {
+            select {
+                case <-quit:
+                case <-ctx.Done():
+            }
+            cancel()
+        }()
+        err := c.readEvents(response.Body, events, quit)
+        select {
+            case <-quit:
+                return nil
+            default:
+                return err
+        }
+    }), nil
+
+}
+
+
+// Get sync status
+func (c *Client) getSyncStatus() (SyncStatusResponse, error) {
+    responseBody, status, err := c.getRequest(RequestSyncStatusPath)
+    if err != nil {
+        return SyncStatusResponse{}, fmt.Errorf("Could not get node sync status: %w", err)
+    } else if status != http.StatusOK {
+        return SyncStatusResponse{}, fmt.Errorf("Could not get node sync status: HTTP status %d; response body: '%s'", status, string(responseBody))
+    }
+    var syncStatus SyncStatusResponse
+    if err := json.Unmarshal(responseBody, &syncStatus); err != nil {
+        return SyncStatusResponse{}, fmt.Errorf("Could not decode node sync status: %w", err)
+    }
+    return syncStatus, nil
+}
+
+
+// Get the eth2 config
+func (c *Client) getEth2Config() (Eth2ConfigResponse, error) {
+    responseBody, status, err := c.getRequest(RequestEth2ConfigPath)
+    if err != nil {
+        return Eth2ConfigResponse{}, fmt.Errorf("Could not get eth2 config: %w", err)
+    } else if status != http.StatusOK {
+        return Eth2ConfigResponse{}, fmt.Errorf("Could not get eth2 config: HTTP status %d; response body: '%s'", status, string(responseBody))
+    }
+    var eth2Config Eth2ConfigResponse
+    if err := json.Unmarshal(responseBody, &eth2Config); err != nil {
+        return Eth2ConfigResponse{}, fmt.Errorf("Could not decode eth2 config: %w", err)
+    }
+    return eth2Config, nil
+}
+
+
+// Get genesis information
+func (c *Client) getGenesis() (GenesisResponse, error) {
+    responseBody, status, err := c.getRequest(RequestGenesisPath)
+    if err != nil {
+        return GenesisResponse{}, fmt.Errorf("Could not get genesis data: %w", err)
+    } else if status != http.StatusOK {
+        return GenesisResponse{}, fmt.Errorf("Could not get genesis data: HTTP status %d; response body: '%s'", status, string(responseBody))
+    }
+    var genesis GenesisResponse
+    if err := json.Unmarshal(responseBody, &genesis); err != nil {
+        return GenesisResponse{}, fmt.Errorf("Could not decode genesis: %w", err)
+    }
+    return genesis, nil
+}
+
+
+// Get finality checkpoints
+func (c *Client) getFinalityCheckpoints(stateId string) (FinalityCheckpointsResponse, error) {
+    responseBody, status, err := c.getRequest(fmt.Sprintf(RequestFinalityCheckpointsPath, stateId))
+    if err != nil {
+        return FinalityCheckpointsResponse{}, fmt.Errorf("Could not get finality checkpoints: %w", err)
+    } else if status != http.StatusOK {
+        return FinalityCheckpointsResponse{}, fmt.Errorf("Could not get finality checkpoints: HTTP status %d; response body: '%s'", status, string(responseBody))
+    }
+    var finalityCheckpoints FinalityCheckpointsResponse
+    if err := json.Unmarshal(responseBody, &finalityCheckpoints); err != nil {
+        return FinalityCheckpointsResponse{}, fmt.Errorf("Could not decode finality checkpoints: %w", err)
+    }
+    return finalityCheckpoints, nil
+}
+
+
+// Get fork
+func (c *Client) getFork(stateId string) (ForkResponse, error) {
+    responseBody, status, err := c.getRequest(fmt.Sprintf(RequestForkPath, stateId))
+    if err != nil {
+        return ForkResponse{}, fmt.Errorf("Could not get fork data: %w", err)
+    } else if status != http.StatusOK {
+        return ForkResponse{}, fmt.Errorf("Could not get fork data: HTTP status %d; response body: '%s'", status, string(responseBody))
+    }
+    var fork ForkResponse
+    if err := json.Unmarshal(responseBody, &fork); err != nil {
+        return ForkResponse{}, fmt.Errorf("Could not decode fork data: %w", err)
+    }
+    return fork, nil
+}
+
+
+// Get validators
+func (c *Client) getValidators(stateId string, pubkeys []string) (ValidatorsResponse, error) {
+    var query string
+    if len(pubkeys) > 0 {
+        query = fmt.Sprintf("?id=%s", strings.Join(pubkeys, ","))
+    }
+    responseBody, status, err := c.getRequest(fmt.Sprintf(RequestValidatorsPath, stateId) + query)
+    if err != nil {
+        return ValidatorsResponse{}, fmt.Errorf("Could not get validators: %w", err)
+    } else if status == http.StatusNotFound && stateId != "head" {
+        return ValidatorsResponse{}, fmt.Errorf("Could not get validators at state %s: %w", stateId, beacon.ErrStateUnavailable)
+    } else if status != http.StatusOK {
+        return ValidatorsResponse{}, fmt.Errorf("Could not get validators: HTTP status %d; response body: '%s'", status, string(responseBody))
+    }
+    var validators ValidatorsResponse
+    if err := json.Unmarshal(responseBody, &validators); err != nil {
+        return ValidatorsResponse{}, fmt.Errorf("Could not decode validators: %w", err)
+    }
+    return validators, nil
+}
+
+
+// Get the state ID for the first slot of an epoch
+func (c *Client) getEpochStateId(epoch uint64) (string, error) {
+    eth2Config, err := c.getEth2Config()
+    if err != nil {
+        return "", err
+    }
+    slot := epoch * uint64(eth2Config.Data.SlotsPerEpoch)
+    return strconv.FormatUint(slot, 10), nil
+}
+
+
+// Get validators by pubkeys and status options
+func (c *Client) getValidatorsByOpts(pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (ValidatorsResponse, error) {
+
+    // Get state ID
+    stateId := "head"
+    if opts != nil {
+        var err error
+        stateId, err = c.getEpochStateId(opts.Epoch)
+        if err != nil {
+            return ValidatorsResponse{}, err
+        }
+    }
+
+    // Get validators
+    return c.getValidatorsByStateId(pubkeys, stateId)
+
+}
+
+
+// Get validators by pubkeys at a beacon state
+func (c *Client) getValidatorsByStateId(pubkeys []types.ValidatorPubkey, stateId string) (ValidatorsResponse, error) {
+
+    // Get all validators & filter by pubkeys if required
+    if c.quirks.FetchAllValidators && len(pubkeys) > MaxRequestValidatorsCount {
+
+        // Get all validators
+        validators, err := c.getValidators(stateId, []string{})
+        if err != nil {
+            return ValidatorsResponse{}, err
+        }
+
+        // Filter validator set by pubkeys and return
+        pubkeySet := make(map[types.ValidatorPubkey]bool, len(pubkeys))
+        for _, pubkey := range pubkeys {
+            pubkeySet[pubkey] = true
+        }
+        response := ValidatorsResponse{}
+        for _, validator := range validators.Data {
+            if pubkeySet[types.BytesToValidatorPubkey(validator.Validator.Pubkey)] {
+                response.Data = append(response.Data, validator)
+            }
+        }
+        return response, nil
+
+    }
+
+    // Load validator data in batches & return
+    data := make([]Validator, 0, len(pubkeys))
+    for bsi := 0; bsi < len(pubkeys); bsi += MaxRequestValidatorsCount {
+
+        // Get batch start & end index
+        vsi := bsi
+        vei := bsi + MaxRequestValidatorsCount
+        if vei > len(pubkeys) { vei = len(pubkeys) }
+
+        // Get validator pubkeys for batch request
+        pubkeysHex := make([]string, vei - vsi)
+        for vi := vsi; vi < vei; vi++ {
+            pubkeysHex[vi - vsi] = hexutil.AddPrefix(pubkeys[vi].Hex())
+        }
+
+        // Get & add validators
+        validators, err := c.getValidators(stateId, pubkeysHex)
+        if err != nil {
+            return ValidatorsResponse{}, err
+        }
+        data = append(data, validators.Data...)
+
+    }
+    return ValidatorsResponse{Data: data}, nil
+
+}
+
+
+// Get attester duties
+func (c *Client) postAttesterDuties(epoch uint64, indices []uinteger) (AttesterDutiesResponse, error) {
+    responseBody, status, err := c.postRequest(fmt.Sprintf(RequestAttesterDutiesPath, epoch), indices)
+    if err != nil {
+        return AttesterDutiesResponse{}, fmt.Errorf("Could not get attester duties for epoch %d: %w", epoch, err)
+    } else if status != http.StatusOK {
+        return AttesterDutiesResponse{}, fmt.Errorf("Could not get attester duties for epoch %d: HTTP status %d; response body: '%s'", epoch, status, string(responseBody))
+    }
+    var duties AttesterDutiesResponse
+    if err := json.Unmarshal(responseBody, &duties); err != nil {
+        return AttesterDutiesResponse{}, fmt.Errorf("Could not decode attester duties: %w", err)
+    }
+    return duties, nil
+}
+
+
+// Get proposer duties
+func (c *Client) getProposerDuties(epoch uint64) (ProposerDutiesResponse, error) {
+    responseBody, status, err := c.getRequest(fmt.Sprintf(RequestProposerDutiesPath, epoch))
+    if err != nil {
+        return ProposerDutiesResponse{}, fmt.Errorf("Could not get proposer duties for epoch %d: %w", epoch, err)
+    } else if status != http.StatusOK {
+        return ProposerDutiesResponse{}, fmt.Errorf("Could not get proposer duties for epoch %d: HTTP status %d; response body: '%s'", epoch, status, string(responseBody))
+    }
+    var duties ProposerDutiesResponse
+    if err := json.Unmarshal(responseBody, &duties); err != nil {
+        return ProposerDutiesResponse{}, fmt.Errorf("Could not decode proposer duties: %w", err)
+    }
+    return duties, nil
+}
+
+
+// Get a block by slot; returns false if there is no block at the slot
+func (c *Client) getBlock(slot uint64) (BlockResponse, bool, error) {
+    responseBody, status, err := c.getRequest(fmt.Sprintf(RequestBlockPath, slot))
+    if err != nil {
+        return BlockResponse{}, false, fmt.Errorf("Could not get block at slot %d: %w", slot, err)
+    } else if status == http.StatusNotFound {
+        return BlockResponse{}, false, nil
+    } else if status != http.StatusOK {
+        return BlockResponse{}, false, fmt.Errorf("Could not get block at slot %d: HTTP status %d; response body: '%s'", slot, status, string(responseBody))
+    }
+    var block BlockResponse
+    if err := json.Unmarshal(responseBody, &block); err != nil {
+        return BlockResponse{}, false, fmt.Errorf("Could not decode block: %w", err)
+    }
+    return block, true, nil
+}
+
+
+// Open the event stream for a set of topics
+func (c *Client) getEventStream(ctx context.Context, topics []string) (*http.Response, error) {
+    request, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(RequestUrlFormat, RequestProtocol, c.providerAddress, fmt.Sprintf(RequestEventsPath, strings.Join(topics, ","))), nil)
+    if err != nil {
+        return nil, err
+    }
+    request.Header.Set("Accept", RequestEventStreamContentType)
+    response, err := http.DefaultClient.Do(request)
+    if err != nil {
+        return nil, fmt.Errorf("Could not subscribe to beacon events: %w: %s", beacon.ErrProviderUnavailable, err.Error())
+    }
+    if response.StatusCode != http.StatusOK {
+        defer response.Body.Close()
+        responseBody, status, err := readResponse(response)
+        if err != nil {
+            return nil, fmt.Errorf("Could not subscribe to beacon events: %w", err)
+        }
+        return nil, fmt.Errorf("Could not subscribe to beacon events: HTTP status %d; response body: '%s'", status, string(responseBody))
+    }
+    return response, nil
+}
+
+
+// Read events from an event stream and dispatch them until the stream fails or quit is closed
+func (c *Client) readEvents(stream io.Reader, events chan<- beacon.Event, quit <-chan struct{}) error {
+    reader := bufio.NewReader(stream)
+    var topic string
+    var data []byte
+    for {
+
+        // Read line
+        line, err := reader.ReadString('\n')
+        if errors.Is(err, io.EOF) {
+            return errors.New("Beacon event stream closed by the beacon node")
+        } else if err != nil {
+            return fmt.Errorf("Could not read beacon event stream: %w", err)
+        }
+        line = strings.TrimRight(line, "\r\n")
+
+        // Add event fields; dispatch event on blank line
+        switch {
+            case strings.HasPrefix(line, "event:"):
+                topic = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+            case strings.HasPrefix(line, "data:"):
+                data = append(data, strings.TrimSpace(strings.TrimPrefix(line, "data:"))...)
+            case line == "":
+                if topic != "" && len(data) > 0 {
+                    e, ok, err := decodeEvent(topic, data)
+                    if err != nil {
+                        return err
+                    }
+                    if ok {
+                        select {
+                            case events <- e:
+                            case <-quit:
+                                return nil
+                        }
+                    }
+                }
+                topic = ""
+                data = nil
+        }
+
+    }
+}
+
+
+// Decode event data by topic; returns false for unknown topics
+func decodeEvent(topic string, data []byte) (beacon.Event, bool, error) {
+    e := beacon.Event{Topic: topic}
+    switch topic {
+        case beacon.EventTopicHead:
+            var head HeadEvent
+            if err := json.Unmarshal(data, &head); err != nil {
+                return beacon.Event{}, false, fmt.Errorf("Could not decode head event: %w", err)
+            }
+            e.Slot = uint64(head.Slot)
+            e.BlockRoot = common.BytesToHash(head.Block)
+        case beacon.EventTopicFinalizedCheckpoint:
+            var checkpoint FinalizedCheckpointEvent
+            if err := json.Unmarshal(data, &checkpoint); err != nil {
+                return beacon.Event{}, false, fmt.Errorf("Could not decode finalized checkpoint event: %w", err)
+            }
+            e.Epoch = uint64(checkpoint.Epoch)
+            e.BlockRoot = common.BytesToHash(checkpoint.Block)
+        case beacon.EventTopicChainReorg:
+            var reorg ChainReorgEvent
+            if err := json.Unmarshal(data, &reorg); err != nil {
+                return beacon.Event{}, false, fmt.Errorf("Could not decode chain reorg event: %w", err)
+            }
+            e.Slot = uint64(reorg.Slot)
+            e.Epoch = uint64(reorg.Epoch)
+            e.Depth = uint64(reorg.Depth)
+            e.BlockRoot = common.BytesToHash(reorg.NewHeadBlock)
+        case beacon.EventTopicVoluntaryExit:
+            var exit VoluntaryExitRequest
+            if err := json.Unmarshal(data, &exit); err != nil {
+                return beacon.Event{}, false, fmt.Errorf("Could not decode voluntary exit event: %w", err)
+            }
+            e.Epoch = uint64(exit.Message.Epoch)
+            e.ValidatorIndex = uint64(exit.Message.ValidatorIndex)
+        default:
+            return beacon.Event{}, false, nil
+    }
+    return e, true, nil
+}
+
+
+// Filter null pubkeys from a pubkey list if required
+func (c *Client) filterPubkeys(pubkeys []types.ValidatorPubkey) []types.ValidatorPubkey {
+    if !c.quirks.FilterNullPubkeys {
+        return pubkeys
+    }
+    filtered := make([]types.ValidatorPubkey, 0, len(pubkeys))
+    for _, pubkey := range pubkeys {
+        if pubkey != (types.ValidatorPubkey{}) {
+            filtered = append(filtered, pubkey)
+        }
+    }
+    return filtered
+}
+
+
+// Send voluntary exit request
+func (c *Client) postVoluntaryExit(request VoluntaryExitRequest) error {
+    responseBody, status, err := c.postRequest(RequestVoluntaryExitPath, request)
+    if err != nil {
+        return fmt.Errorf("Could not broadcast exit for validator at index %d: %w", request.Message.ValidatorIndex, err)
+    } else if status != http.StatusOK {
+        return fmt.Errorf("Could not broadcast exit for validator at index %d: HTTP status %d; response body: '%s'", request.Message.ValidatorIndex, status, string(responseBody))
+    }
+    return nil
+}
+
+
+// Make a GET request to the beacon node
+func (c *Client) getRequest(requestPath string) ([]byte, int, error) {
+
+    // Send request
+    response, err := http.Get(fmt.Sprintf(RequestUrlFormat, RequestProtocol, c.providerAddress, requestPath))
+    if err != nil {
+        return []byte{}, 0, fmt.Errorf("%w: %s", beacon.ErrProviderUnavailable, err.Error())
+    }
+    defer response.Body.Close()
+
+    // Get response
+    return readResponse(response)
+
+}
+
+
+// Make a POST request to the beacon node
+func (c *Client) postRequest(requestPath string, requestBody interface{}) ([]byte, int, error) {
+
+    // Get request body
+    requestBodyBytes, err := json.Marshal(requestBody)
+    if err != nil {
+        return []byte{}, 0, err
+    }
+    requestBodyReader := bytes.NewReader(requestBodyBytes)
+
+    // Send request
+    response, err := http.Post(fmt.Sprintf(RequestUrlFormat, RequestProtocol, c.providerAddress, requestPath), RequestContentType, requestBodyReader)
+    if err != nil {
+        return []byte{}, 0, fmt.Errorf("%w: %s", beacon.ErrProviderUnavailable, err.Error())
+    }
+    defer response.Body.Close()
+
+    // Get response
+    return readResponse(response)
+
+}
+
+
+// Read a response body & status code from the beacon node
+// Server errors are returned as provider errors rather than as status codes
+func readResponse(response *http.Response) ([]byte, int, error) {
+
+    // Read body
+    body, err := ioutil.ReadAll(response.Body)
+    if err != nil {
+        return []byte{}, 0, fmt.Errorf("%w: %s", beacon.ErrProviderUnavailable, err.Error())
+    }
+
+    // Check for server errors
+    if response.StatusCode >= http.StatusInternalServerError {
+        return []byte{}, 0, fmt.Errorf("%w: HTTP status %d; response body: '%s'", beacon.ErrProviderUnavailable, response.StatusCode, string(body))
+    }
+
+    // Return
+    return body, response.StatusCode, nil
+
+}
+
@@ -1,4 +1,4 @@
-package lighthouse
+package standard
 
 import (
     "encoding/hex"
@@ -79,6 +79,56 @@ type Validator struct {
         WithdrawableEpoch uinteger          `json:"withdrawable_epoch"`
     }                                   `json:"validator"`
 }
+type AttesterDutiesResponse struct {
+    Data []struct {
+        Pubkey byteArray                    `json:"pubkey"`
+        ValidatorIndex uinteger             `json:"validator_index"`
+        CommitteeIndex uinteger             `json:"committee_index"`
+        ValidatorCommitteeIndex uinteger    `json:"validator_committee_index"`
+        Slot uinteger                       `json:"slot"`
+    }                                   `json:"data"`
+}
+type ProposerDutiesResponse struct {
+    Data []struct {
+        Pubkey byteArray                    `json:"pubkey"`
+        ValidatorIndex uinteger             `json:"validator_index"`
+        Slot uinteger                       `json:"slot"`
+    }                                   `json:"data"`
+}
+type BlockResponse struct {
+    Data struct {
+        Message struct {
+            Slot uinteger                       `json:"slot"`
+            ProposerIndex uinteger              `json:"proposer_index"`
+            Body struct {
+                Attestations []struct {
+                    AggregationBits byteArray           `json:"aggregation_bits"`
+                    Data struct {
+                        Slot uinteger                       `json:"slot"`
+                        Index uinteger                      `json:"index"`
+                    }                                   `json:"data"`
+                }                                   `json:"attestations"`
+            }                                   `json:"body"`
+        }                                   `json:"message"`
+    }                                   `json:"data"`
+}
+
+
+// Event types
+type HeadEvent struct {
+    Slot uinteger                       `json:"slot"`
+    Block byteArray                     `json:"block"`
+}
+type FinalizedCheckpointEvent struct {
+    Block byteArray                     `json:"block"`
+    Epoch uinteger                      `json:"epoch"`
+}
+type ChainReorgEvent struct {
+    Slot uinteger                       `json:"slot"`
+    Depth uinteger                      `json:"depth"`
+    NewHeadBlock byteArray              `json:"new_head_block"`
+    Epoch uinteger                      `json:"epoch"`
+}
 
 
 // Unsigned integer type
@@ -1,11 +1,13 @@
 package config
 
 import (
+    "errors"
     "fmt"
     "io/ioutil"
     "math/big"
     "os"
     "strconv"
+    "time"
 
     "github.com/imdario/mergo"
     "github.com/urfave/cli"
@@ -15,6 +17,52 @@ import (
 )
 
 
+// Defaults
+const DefaultMaxExchangeRateChange = 1.0 // 1%
+const DefaultValidatorRestartTimeout = 2 * time.Minute
+
+
+// Validator restart modes
+const (
+    ValidatorRestartDocker = "docker"
+    ValidatorRestartCommand = "command"
+    ValidatorRestartSystemdUnit = "systemd-unit"
+    ValidatorRestartNone = "none"
+)
+
+
+// Default slashing protection interchange tools by Eth 2.0 client
+// Tool arguments may contain {file} (interchange file path), {dir} (interchange folder path), {network} and {provider} placeholders
+var defaultInterchangeTools = map[string]InterchangeTool{
+    "lighthouse": InterchangeTool{
+        Service: "validator",
+        Entrypoint: "lighthouse",
+        ExportArgs: []string{"account", "validator", "slashing-protection", "export", "{file}", "--datadir", "/validators/lighthouse", "--network", "{network}"},
+        ImportArgs: []string{"account", "validator", "slashing-protection", "import", "{file}", "--datadir", "/validators/lighthouse", "--network", "{network}"},
+    },
+    "nimbus": InterchangeTool{
+        Service: "eth2",
+        Entrypoint: "/home/user/nimbus-eth2/build/nimbus_beacon_node",
+        ExportArgs: []string{"--data-dir=/ethclient/nimbus", "--validators-dir=/validators/nimbus/validators", "slashingdb", "export", "{file}"},
+        ImportArgs: []string{"--data-dir=/ethclient/nimbus", "--validators-dir=/validators/nimbus/validators", "slashingdb", "import", "{file}"},
+    },
+    "prysm": InterchangeTool{
+        Service: "validator",
+        Entrypoint: "/app/cmd/validator/validator",
+        ExportArgs: []string{"slashing-protection", "export", "--accept-terms-of-use", "--datadir=/validators/prysm-non-hd/direct", "--beacon-rpc-provider={provider}", "--slashing-protection-export-dir={dir}"},
+        ImportArgs: []string{"slashing-protection", "import", "--accept-terms-of-use", "--datadir=/validators/prysm-non-hd/direct", "--slashing-protection-json-file={file}"},
+        ExportFile: "slashing_protection.json",
+    },
+    "teku": InterchangeTool{
+        Service: "validator",
+        Entrypoint: "/opt/teku/bin/teku",
+        ExportArgs: []string{"slashing-protection", "export", "--data-path=/validators/teku", "--to={file}"},
+        ImportArgs: []string{"slashing-protection", "import", "--data-path=/validators/teku", "--from={file}"},
+    },
+}
+const DefaultInterchangeFile = "slashing-protection.json"
+
+
 // Rocket Pool config
 type RocketPoolConfig struct {
     Rocketpool struct {
@@ -26,9 +74,35 @@ type RocketPoolConfig struct {
         PasswordPath string             `yaml:"passwordPath,omitempty"`
         WalletPath string               `yaml:"walletPath,omitempty"`
         ValidatorKeychainPath string    `yaml:"validatorKeychainPath,omitempty"`
+        DatabasePath string             `yaml:"databasePath,omitempty"`
         ValidatorRestartCommand string  `yaml:"validatorRestartCommand,omitempty"`
+        ValidatorRestart struct {
+            Mode string                 `yaml:"mode,omitempty"`
+            Container string            `yaml:"container,omitempty"`
+            Command string              `yaml:"command,omitempty"`
+            Args []string               `yaml:"args,omitempty"`
+            Env []string                `yaml:"env,omitempty"`
+            Timeout string              `yaml:"timeout,omitempty"`
+            Unit string                 `yaml:"unit,omitempty"`
+        }                               `yaml:"validatorRestart,omitempty"`
+        KeymanagerUrl string            `yaml:"keymanagerUrl,omitempty"`
+        KeymanagerTokenPath string      `yaml:"keymanagerTokenPath,omitempty"`
+        RemoteSigner struct {
+            Url string                  `yaml:"url,omitempty"`
+            TokenPath string            `yaml:"tokenPath,omitempty"`
+            ValidatorUrl string         `yaml:"validatorUrl,omitempty"`
+        }                               `yaml:"remoteSigner,omitempty"`
         GasPrice string                 `yaml:"gasPrice,omitempty"`
         GasLimit string                 `yaml:"gasLimit,omitempty"`
+        MetricsAddress string           `yaml:"metricsAddress,omitempty"`
+        LogLevel string                 `yaml:"logLevel,omitempty"`
+        LogFormat string                `yaml:"logFormat,omitempty"`
+        MaxExchangeRateChange string    `yaml:"maxExchangeRateChange,omitempty"`
+        AutoRefund bool                 `yaml:"autoRefund,omitempty"`
+        AutoWithdraw bool               `yaml:"autoWithdraw,omitempty"`
+        AutoClose bool                  `yaml:"autoClose,omitempty"`
+        AutoMaxGasPrice string          `yaml:"autoMaxGasPrice,omitempty"`
+        AutoMinRefund string            `yaml:"autoMinRefund,omitempty"`
     }                                   `yaml:"smartnode,omitempty"`
     Chains struct {
         Eth1 Chain                      `yaml:"eth1,omitempty"`
@@ -38,6 +112,10 @@ type RocketPoolConfig struct {
 type Chain struct {
     Provider string                     `yaml:"provider,omitempty"`
     WsProvider string                   `yaml:"wsProvider,omitempty"`
+    CheckProvider string                `yaml:"checkProvider,omitempty"`
+    FallbackProviders []string          `yaml:"fallbackProviders,omitempty"`
+    EventsProvider string               `yaml:"eventsProvider,omitempty"`
+    Network string                      `yaml:"network,omitempty"`
     ChainID string                      `yaml:"chainID,omitempty"`
     Client struct {
         Options []ClientOption          `yaml:"options,omitempty"`
@@ -54,6 +132,7 @@ type ClientOption struct {
     ValidatorImage string               `yaml:"validatorImage,omitempty"`
     Link string                         `yaml:"link,omitempty"`
     Params []ClientParam                `yaml:"params,omitempty"`
+    Interchange InterchangeTool         `yaml:"interchange,omitempty"`
 }
 type ClientParam struct {
     Name string                         `yaml:"name,omitempty"`
@@ -62,6 +141,13 @@ type ClientParam struct {
     Required bool                       `yaml:"required,omitempty"`
     Regex string                        `yaml:"regex,omitempty"`
 }
+type InterchangeTool struct {
+    Service string                      `yaml:"service,omitempty"`
+    Entrypoint string                   `yaml:"entrypoint,omitempty"`
+    ExportArgs []string                 `yaml:"exportArgs,omitempty"`
+    ImportArgs []string                 `yaml:"importArgs,omitempty"`
+    ExportFile string                   `yaml:"exportFile,omitempty"`
+}
 type UserParam struct {
     Env string                          `yaml:"env,omitempty"`
     Value string                        `yaml:"value"`
@@ -85,6 +171,18 @@ func (chain *Chain) GetSelectedClient() *ClientOption {
 }
 
 
+// Get a chain's providers in priority order; the primary provider followed by any fallback providers
+func (chain *Chain) GetProviders() []string {
+    providers := []string{chain.Provider}
+    for _, provider := range chain.FallbackProviders {
+        if provider != "" && provider != chain.Provider {
+            providers = append(providers, provider)
+        }
+    }
+    return providers
+}
+
+
 // Get the beacon & validator images for a client
 func (client *ClientOption) GetBeaconImage() string {
     if client.BeaconImage != "" {
@@ -102,6 +200,23 @@ func (client *ClientOption) GetValidatorImage() string {
 }
 
 
+// Get the slashing protection interchange tool for a client
+// Settings in the client config override the client's default tool
+func (client *ClientOption) GetInterchangeTool() (InterchangeTool, error) {
+    tool := defaultInterchangeTools[client.ID]
+    if client.Interchange.Service != "" { tool.Service = client.Interchange.Service }
+    if client.Interchange.Entrypoint != "" { tool.Entrypoint = client.Interchange.Entrypoint }
+    if len(client.Interchange.ExportArgs) > 0 { tool.ExportArgs = client.Interchange.ExportArgs }
+    if len(client.Interchange.ImportArgs) > 0 { tool.ImportArgs = client.Interchange.ImportArgs }
+    if client.Interchange.ExportFile != "" { tool.ExportFile = client.Interchange.ExportFile }
+    if tool.ExportFile == "" { tool.ExportFile = DefaultInterchangeFile }
+    if tool.Service == "" || tool.Entrypoint == "" {
+        return InterchangeTool{}, fmt.Errorf("The %s client does not support slashing protection interchange", client.Name)
+    }
+    return tool, nil
+}
+
+
 // Serialize a config to yaml bytes
 func (config *RocketPoolConfig) Serialize() ([]byte, error) {
     bytes, err := yaml.Marshal(config)
@@ -184,10 +299,16 @@ func getCliConfig(c *cli.Context) RocketPoolConfig {
     config.Smartnode.PasswordPath = c.GlobalString("password")
     config.Smartnode.WalletPath = c.GlobalString("wallet")
     config.Smartnode.ValidatorKeychainPath = c.GlobalString("validatorKeychain")
+    config.Smartnode.DatabasePath = c.GlobalString("database")
     config.Smartnode.GasPrice = c.GlobalString("gasPrice")
     config.Smartnode.GasLimit = c.GlobalString("gasLimit")
+    config.Smartnode.MetricsAddress = c.GlobalString("metricsAddress")
+    config.Smartnode.LogLevel = c.GlobalString("logLevel")
+    config.Smartnode.LogFormat = c.GlobalString("logFormat")
     config.Chains.Eth1.Provider = c.GlobalString("eth1Provider")
     config.Chains.Eth2.Provider = c.GlobalString("eth2Provider")
+    config.Chains.Eth1.CheckProvider = c.GlobalString("eth1CheckProvider")
+    config.Chains.Eth2.CheckProvider = c.GlobalString("eth2CheckProvider")
     return config
 }
 
@@ -236,3 +357,120 @@ func (config *RocketPoolConfig) GetGasLimit() (uint64, error) {
 
 }
 
+
+// Parse and return the maximum rETH exchange rate change per balances submission as a fraction
+func (config *RocketPoolConfig) GetMaxExchangeRateChange() (float64, error) {
+
+    // No maximum change specified
+    if config.Smartnode.MaxExchangeRateChange == "" {
+        return DefaultMaxExchangeRateChange / 100, nil
+    }
+
+    // Parse maximum change as a percentage
+    maxChangePercent, err := strconv.ParseFloat(config.Smartnode.MaxExchangeRateChange, 64)
+    if err != nil {
+        return 0, fmt.Errorf("Invalid maximum exchange rate change '%s': %w", config.Smartnode.MaxExchangeRateChange, err)
+    }
+    if maxChangePercent <= 0 {
+        return 0, errors.New("Maximum exchange rate change must be greater than zero")
+    }
+
+    // Return
+    return maxChangePercent / 100, nil
+
+}
+
+
+// Parse and return the maximum gas price in wei for automatic minipool transactions
+func (config *RocketPoolConfig) GetAutoMaxGasPrice() (*big.Int, error) {
+
+    // No maximum gas price specified
+    if config.Smartnode.AutoMaxGasPrice == "" {
+        return nil, nil
+    }
+
+    // Parse maximum gas price in gwei
+    maxGasPriceGwei, err := strconv.ParseFloat(config.Smartnode.AutoMaxGasPrice, 64)
+    if err != nil {
+        return nil, fmt.Errorf("Invalid automatic transaction maximum gas price '%s': %w", config.Smartnode.AutoMaxGasPrice, err)
+    }
+
+    // Return nil if maximum gas price is set to zero
+    if maxGasPriceGwei == 0 {
+        return nil, nil
+    }
+
+    // Return maximum gas price in wei
+    return eth.GweiToWei(maxGasPriceGwei), nil
+
+}
+
+
+// Parse and return the minimum refund amount in wei for automatic minipool refunds
+func (config *RocketPoolConfig) GetAutoMinRefund() (*big.Int, error) {
+
+    // No minimum refund specified
+    if config.Smartnode.AutoMinRefund == "" {
+        return big.NewInt(0), nil
+    }
+
+    // Parse minimum refund in ETH
+    minRefundEth, err := strconv.ParseFloat(config.Smartnode.AutoMinRefund, 64)
+    if err != nil {
+        return nil, fmt.Errorf("Invalid automatic refund minimum amount '%s': %w", config.Smartnode.AutoMinRefund, err)
+    }
+
+    // Return minimum refund in wei
+    return eth.EthToWei(minRefundEth), nil
+
+}
+
+
+// Get the validator restart mode
+// Defaults to docker when running inside a container and to the legacy validator restart command otherwise
+func (config *RocketPoolConfig) GetValidatorRestartMode(insideContainer bool) (string, error) {
+    switch mode := config.Smartnode.ValidatorRestart.Mode; mode {
+        case "":
+            if insideContainer {
+                return ValidatorRestartDocker, nil
+            }
+            return ValidatorRestartCommand, nil
+        case ValidatorRestartDocker, ValidatorRestartCommand, ValidatorRestartSystemdUnit, ValidatorRestartNone:
+            return mode, nil
+        default:
+            return "", fmt.Errorf("Invalid validator restart mode '%s'", mode)
+    }
+}
+
+
+// Get the remote signer URL used by validator clients to sign
+// Defaults to the URL used by the smart node to import keys
+func (config *RocketPoolConfig) GetRemoteSignerValidatorUrl() string {
+    if config.Smartnode.RemoteSigner.ValidatorUrl != "" {
+        return config.Smartnode.RemoteSigner.ValidatorUrl
+    }
+    return config.Smartnode.RemoteSigner.Url
+}
+
+
+// Parse and return the validator restart timeout
+func (config *RocketPoolConfig) GetValidatorRestartTimeout() (time.Duration, error) {
+
+    // No timeout specified
+    if config.Smartnode.ValidatorRestart.Timeout == "" {
+        return DefaultValidatorRestartTimeout, nil
+    }
+
+    // Parse timeout
+    timeout, err := time.ParseDuration(config.Smartnode.ValidatorRestart.Timeout)
+    if err != nil {
+        return 0, fmt.Errorf("Invalid validator restart timeout '%s': %w", config.Smartnode.ValidatorRestart.Timeout, err)
+    }
+    if timeout <= 0 {
+        return 0, errors.New("Validator restart timeout must be greater than zero")
+    }
+
+    // Return
+    return timeout, nil
+
+}
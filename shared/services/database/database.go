@@ -0,0 +1,417 @@
+package database
+
+import (
+    "bufio"
+    "encoding/json"
+    "fmt"
+    "io/ioutil"
+    "math/big"
+    "os"
+    "path/filepath"
+    "sync"
+    "time"
+
+    "github.com/ethereum/go-ethereum/common"
+    "github.com/rocket-pool/rocketpool-go/types"
+
+    "github.com/rocket-pool/smartnode/shared/utils/files"
+)
+
+
+// Config
+const (
+    FileMode = 0600
+    DirMode = 0700
+    SubmissionsFile = "submissions.jsonl"
+    MinipoolsFile = "minipools.json"
+    MinipoolIndexFile = "minipool-index.json"
+    ValidatorRestartFile = "validator-restart.json"
+    MaxSubmissionLineSize = 1024 * 1024
+)
+
+
+// Submission statuses
+const (
+    SubmissionSucceeded = "success"
+    SubmissionFailed = "failed"
+    SubmissionRejected = "rejected"
+)
+
+
+// Submission journal record
+type Submission struct {
+    Time time.Time                      `json:"time"`
+    Task string                         `json:"task"`
+    Method string                       `json:"method"`
+    Arguments map[string]string         `json:"arguments"`
+    Block uint64                        `json:"block"`
+    TxHash common.Hash                  `json:"txHash"`
+    Status string                       `json:"status"`
+    Error string                        `json:"error,omitempty"`
+}
+
+
+// Cached minipool static data
+type MinipoolDetails struct {
+    Address common.Address              `json:"address"`
+    ValidatorPubkey types.ValidatorPubkey `json:"validatorPubkey"`
+    NodeDepositBalance *big.Int         `json:"nodeDepositBalance,omitempty"`
+    UserDepositBalance *big.Int         `json:"userDepositBalance,omitempty"`
+    UserDepositTime uint64              `json:"userDepositTime,omitempty"`
+    DepositsFinal bool                  `json:"depositsFinal"`
+    DepositsFinalBlock uint64           `json:"depositsFinalBlock,omitempty"`
+}
+
+
+// Network minipool index at a block
+type MinipoolIndex struct {
+    Block uint64                        `json:"block"`
+    BlockHash common.Hash               `json:"blockHash"`
+    Minipools map[common.Address]IndexedMinipool `json:"minipools"`
+}
+type IndexedMinipool struct {
+    Status types.MinipoolStatus         `json:"status"`
+    NodeFee float64                     `json:"nodeFee"`
+    NodeDepositBalance *big.Int         `json:"nodeDepositBalance"`
+    UserDepositBalance *big.Int         `json:"userDepositBalance"`
+    UserDepositTime uint64              `json:"userDepositTime"`
+    WithdrawalProcessed bool            `json:"withdrawalProcessed"`
+}
+
+
+// Validator restart state
+type ValidatorRestart struct {
+    Pending bool                        `json:"pending"`
+    LastRestart time.Time               `json:"lastRestart"`
+}
+
+
+// Local smartnode database
+// Submissions are appended to a JSON lines journal; minipool data is cached in memory and saved as a JSON file
+type Database struct {
+    path string
+    minipools map[common.Address]MinipoolDetails
+    minipoolsLoaded bool
+    minipoolsDirty bool
+    submissionsLock sync.Mutex
+    minipoolsLock sync.Mutex
+    minipoolIndexLock sync.Mutex
+    validatorRestartLock sync.Mutex
+}
+
+
+// Create new database
+func NewDatabase(path string) *Database {
+    return &Database{
+        path: path,
+        minipools: make(map[common.Address]MinipoolDetails),
+    }
+}
+
+
+// Add a submission to the journal
+func (db *Database) AddSubmission(submission Submission) error {
+
+    // Lock journal
+    db.submissionsLock.Lock()
+    defer db.submissionsLock.Unlock()
+
+    // Encode submission
+    submissionBytes, err := json.Marshal(submission)
+    if err != nil {
+        return fmt.Errorf("Could not encode submission: %w", err)
+    }
+
+    // Initialize database directory
+    if err := os.MkdirAll(db.path, DirMode); err != nil {
+        return fmt.Errorf("Could not create database directory: %w", err)
+    }
+
+    // Append to journal
+    file, err := os.OpenFile(filepath.Join(db.path, SubmissionsFile), os.O_APPEND | os.O_CREATE | os.O_WRONLY, FileMode)
+    if err != nil {
+        return fmt.Errorf("Could not open submission journal: %w", err)
+    }
+    defer file.Close()
+    if _, err := file.Write(append(submissionBytes, '\n')); err != nil {
+        return fmt.Errorf("Could not write to submission journal: %w", err)
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Get all submissions from the journal, oldest first
+func (db *Database) GetSubmissions() ([]Submission, error) {
+
+    // Lock journal
+    db.submissionsLock.Lock()
+    defer db.submissionsLock.Unlock()
+
+    // Open journal; return no submissions if it does not exist
+    file, err := os.Open(filepath.Join(db.path, SubmissionsFile))
+    if os.IsNotExist(err) {
+        return []Submission{}, nil
+    } else if err != nil {
+        return []Submission{}, fmt.Errorf("Could not open submission journal: %w", err)
+    }
+    defer file.Close()
+
+    // Read submissions
+    submissions := []Submission{}
+    scanner := bufio.NewScanner(file)
+    scanner.Buffer(make([]byte, 0, 64 * 1024), MaxSubmissionLineSize)
+    for scanner.Scan() {
+        line := scanner.Bytes()
+        if len(line) == 0 {
+            continue
+        }
+        var submission Submission
+        if err := json.Unmarshal(line, &submission); err != nil {
+            return []Submission{}, fmt.Errorf("Could not decode submission journal entry: %w", err)
+        }
+        submissions = append(submissions, submission)
+    }
+    if err := scanner.Err(); err != nil {
+        return []Submission{}, fmt.Errorf("Could not read submission journal: %w", err)
+    }
+
+    // Return
+    return submissions, nil
+
+}
+
+
+// Get cached minipool data
+func (db *Database) GetMinipoolDetails(address common.Address) (MinipoolDetails, bool, error) {
+    db.minipoolsLock.Lock()
+    defer db.minipoolsLock.Unlock()
+    if err := db.loadMinipools(); err != nil {
+        return MinipoolDetails{}, false, err
+    }
+    details, ok := db.minipools[address]
+    return details, ok, nil
+}
+
+
+// Cache a minipool's validator pubkey
+// Changes are held in memory until the minipool cache is saved
+func (db *Database) SetMinipoolPubkey(address common.Address, pubkey types.ValidatorPubkey) error {
+    return db.updateMinipoolDetails(address, func(details *MinipoolDetails) {
+        details.ValidatorPubkey = pubkey
+    })
+}
+
+
+// Cache a minipool's final deposit balances & user deposit time, and the block they were known to be final at
+// Changes are held in memory until the minipool cache is saved
+func (db *Database) SetMinipoolDeposits(address common.Address, nodeDepositBalance *big.Int, userDepositBalance *big.Int, userDepositTime uint64, finalBlock uint64) error {
+    return db.updateMinipoolDetails(address, func(details *MinipoolDetails) {
+        details.NodeDepositBalance = nodeDepositBalance
+        details.UserDepositBalance = userDepositBalance
+        details.UserDepositTime = userDepositTime
+        details.DepositsFinal = true
+        details.DepositsFinalBlock = finalBlock
+    })
+}
+
+
+// Save the minipool cache to disk if it has changed
+func (db *Database) SaveMinipools() error {
+
+    // Lock minipool cache
+    db.minipoolsLock.Lock()
+    defer db.minipoolsLock.Unlock()
+
+    // Check for changes
+    if !db.minipoolsDirty {
+        return nil
+    }
+
+    // Encode minipool cache
+    minipools := make([]MinipoolDetails, 0, len(db.minipools))
+    for _, details := range db.minipools {
+        minipools = append(minipools, details)
+    }
+    minipoolsBytes, err := json.Marshal(minipools)
+    if err != nil {
+        return fmt.Errorf("Could not encode minipool cache: %w", err)
+    }
+
+    // Write to disk
+    if err := db.writeFile(MinipoolsFile, minipoolsBytes); err != nil {
+        return fmt.Errorf("Could not write minipool cache: %w", err)
+    }
+
+    // Update dirty status & return
+    db.minipoolsDirty = false
+    return nil
+
+}
+
+
+// Get the network minipool index
+// Returns an empty index if none has been saved
+func (db *Database) GetMinipoolIndex() (MinipoolIndex, error) {
+
+    // Lock minipool index
+    db.minipoolIndexLock.Lock()
+    defer db.minipoolIndexLock.Unlock()
+
+    // Read from disk; return empty index if it does not exist
+    indexBytes, err := ioutil.ReadFile(filepath.Join(db.path, MinipoolIndexFile))
+    if os.IsNotExist(err) {
+        return MinipoolIndex{Minipools: make(map[common.Address]IndexedMinipool)}, nil
+    } else if err != nil {
+        return MinipoolIndex{}, fmt.Errorf("Could not read minipool index: %w", err)
+    }
+
+    // Decode minipool index
+    var index MinipoolIndex
+    if err := json.Unmarshal(indexBytes, &index); err != nil {
+        return MinipoolIndex{}, fmt.Errorf("Could not decode minipool index: %w", err)
+    }
+    if index.Minipools == nil {
+        index.Minipools = make(map[common.Address]IndexedMinipool)
+    }
+
+    // Return
+    return index, nil
+
+}
+
+
+// Save the network minipool index
+func (db *Database) SaveMinipoolIndex(index MinipoolIndex) error {
+
+    // Lock minipool index
+    db.minipoolIndexLock.Lock()
+    defer db.minipoolIndexLock.Unlock()
+
+    // Encode minipool index
+    indexBytes, err := json.Marshal(index)
+    if err != nil {
+        return fmt.Errorf("Could not encode minipool index: %w", err)
+    }
+
+    // Write to disk
+    if err := db.writeFile(MinipoolIndexFile, indexBytes); err != nil {
+        return fmt.Errorf("Could not write minipool index: %w", err)
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Get the validator restart state
+// Returns no pending restart if none has been saved
+func (db *Database) GetValidatorRestart() (ValidatorRestart, error) {
+
+    // Lock validator restart state
+    db.validatorRestartLock.Lock()
+    defer db.validatorRestartLock.Unlock()
+
+    // Read from disk; return empty state if it does not exist
+    restartBytes, err := ioutil.ReadFile(filepath.Join(db.path, ValidatorRestartFile))
+    if os.IsNotExist(err) {
+        return ValidatorRestart{}, nil
+    } else if err != nil {
+        return ValidatorRestart{}, fmt.Errorf("Could not read validator restart state: %w", err)
+    }
+
+    // Decode validator restart state
+    var restart ValidatorRestart
+    if err := json.Unmarshal(restartBytes, &restart); err != nil {
+        return ValidatorRestart{}, fmt.Errorf("Could not decode validator restart state: %w", err)
+    }
+
+    // Return
+    return restart, nil
+
+}
+
+
+// Save the validator restart state
+func (db *Database) SaveValidatorRestart(restart ValidatorRestart) error {
+
+    // Lock validator restart state
+    db.validatorRestartLock.Lock()
+    defer db.validatorRestartLock.Unlock()
+
+    // Encode validator restart state
+    restartBytes, err := json.Marshal(restart)
+    if err != nil {
+        return fmt.Errorf("Could not encode validator restart state: %w", err)
+    }
+
+    // Write to disk
+    if err := db.writeFile(ValidatorRestartFile, restartBytes); err != nil {
+        return fmt.Errorf("Could not write validator restart state: %w", err)
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Update cached minipool data in place
+func (db *Database) updateMinipoolDetails(address common.Address, update func(*MinipoolDetails)) error {
+    db.minipoolsLock.Lock()
+    defer db.minipoolsLock.Unlock()
+    if err := db.loadMinipools(); err != nil {
+        return err
+    }
+    details := db.minipools[address]
+    details.Address = address
+    update(&details)
+    db.minipools[address] = details
+    db.minipoolsDirty = true
+    return nil
+}
+
+
+// Load the minipool cache from disk if not already loaded
+func (db *Database) loadMinipools() error {
+
+    // Check if loaded
+    if db.minipoolsLoaded {
+        return nil
+    }
+
+    // Read from disk; use empty cache if it does not exist
+    minipoolsBytes, err := ioutil.ReadFile(filepath.Join(db.path, MinipoolsFile))
+    if os.IsNotExist(err) {
+        db.minipoolsLoaded = true
+        return nil
+    } else if err != nil {
+        return fmt.Errorf("Could not read minipool cache: %w", err)
+    }
+
+    // Decode minipool cache
+    var minipools []MinipoolDetails
+    if err := json.Unmarshal(minipoolsBytes, &minipools); err != nil {
+        return fmt.Errorf("Could not decode minipool cache: %w", err)
+    }
+    for _, details := range minipools {
+        db.minipools[details.Address] = details
+    }
+
+    // Update loaded status & return
+    db.minipoolsLoaded = true
+    return nil
+
+}
+
+
+// Write a database file atomically via a temporary file
+func (db *Database) writeFile(name string, data []byte) error {
+    if err := os.MkdirAll(db.path, DirMode); err != nil {
+        return err
+    }
+    return files.WriteFileAtomic(filepath.Join(db.path, name), data, FileMode)
+}
@@ -0,0 +1,130 @@
+package events
+
+import (
+    "errors"
+    "fmt"
+    "sync"
+    "time"
+
+    "github.com/rocket-pool/smartnode/shared/services/beacon"
+    "github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+
+// Settings
+var beaconFinalityPollInterval, _ = time.ParseDuration("12s")
+var beaconResubscribeInterval, _ = time.ParseDuration("30s")
+
+
+// Beacon finality watcher
+// Subscribes to finalized checkpoint events and dispatches handlers when the finalized epoch changes
+// The beacon head is polled instead while the event subscription is unavailable
+type BeaconFinalityWatcher struct {
+    bc beacon.Client
+    log log.Logger
+    handlers []func(head beacon.BeaconHead)
+    lock sync.Mutex
+    finalizedEpoch uint64
+    initialized bool
+}
+
+
+// Create new beacon finality watcher
+func NewBeaconFinalityWatcher(bc beacon.Client, logger log.Logger) *BeaconFinalityWatcher {
+    return &BeaconFinalityWatcher{
+        bc: bc,
+        log: logger,
+        handlers: []func(head beacon.BeaconHead){},
+    }
+}
+
+
+// Add a finality change handler
+func (w *BeaconFinalityWatcher) OnFinalityChange(handler func(head beacon.BeaconHead)) {
+    w.lock.Lock()
+    defer w.lock.Unlock()
+    w.handlers = append(w.handlers, handler)
+}
+
+
+// Start watching for finality changes
+func (w *BeaconFinalityWatcher) Start() {
+    go (func() {
+        for {
+            if err := w.watch(); err != nil {
+                w.log.Warn("Beacon finality subscription dropped, polling the beacon head until resubscribed", "error", err, "retryIn", beaconResubscribeInterval)
+            }
+            w.poll(beaconResubscribeInterval)
+        }
+    })()
+}
+
+
+// Subscribe to finalized checkpoint events and check the beacon head on each until the subscription fails
+func (w *BeaconFinalityWatcher) watch() error {
+
+    // Subscribe to finalized checkpoints
+    events := make(chan beacon.Event)
+    sub, err := w.bc.SubscribeEvents([]string{beacon.EventTopicFinalizedCheckpoint}, events)
+    if err != nil {
+        return fmt.Errorf("Could not subscribe to beacon events: %w", err)
+    }
+    defer sub.Unsubscribe()
+
+    // Log
+    w.log.Info("Subscribed to beacon finality events.")
+
+    // Check for finality changes missed while unsubscribed
+    w.checkHead()
+
+    // Check beacon head on finalized checkpoints
+    for {
+        select {
+            case err := <-sub.Err():
+                if err == nil {
+                    err = errors.New("Subscription closed")
+                }
+                return err
+            case e := <-events:
+                w.log.Debug("Received beacon finalized checkpoint", "epoch", e.Epoch)
+                w.checkHead()
+        }
+    }
+
+}
+
+
+// Poll the beacon head for a period
+func (w *BeaconFinalityWatcher) poll(duration time.Duration) {
+    for end := time.Now().Add(duration); time.Now().Before(end); {
+        w.checkHead()
+        time.Sleep(beaconFinalityPollInterval)
+    }
+}
+
+
+// Get the beacon head and dispatch handlers if the finalized epoch has changed
+func (w *BeaconFinalityWatcher) checkHead() {
+
+    // Get beacon head
+    head, err := w.bc.GetBeaconHead()
+    if err != nil {
+        w.log.Error("Could not check beacon finality", "error", err)
+        return
+    }
+
+    // Dispatch handlers on finalized epoch change
+    if w.initialized && head.FinalizedEpoch > w.finalizedEpoch {
+        w.lock.Lock()
+        handlers := w.handlers
+        w.lock.Unlock()
+        w.log.Debug("Beacon finality changed", "finalizedEpoch", head.FinalizedEpoch)
+        for _, handler := range handlers {
+            handler(head)
+        }
+    }
+    w.finalizedEpoch = head.FinalizedEpoch
+    w.initialized = true
+
+}
+
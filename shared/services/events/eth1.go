@@ -0,0 +1,107 @@
+package events
+
+import (
+    "context"
+    "fmt"
+    "sync"
+    "time"
+
+    "github.com/ethereum/go-ethereum/core/types"
+    "github.com/ethereum/go-ethereum/ethclient"
+
+    "github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+
+// Settings
+var eth1ReconnectInterval, _ = time.ParseDuration("30s")
+
+
+// Eth1 new header watcher
+// Subscribes to new headers over a websocket connection, reconnecting if the connection drops
+type Eth1HeadWatcher struct {
+    wsProvider string
+    log log.Logger
+    handlers []func(header *types.Header)
+    lock sync.Mutex
+}
+
+
+// Create new eth1 header watcher
+func NewEth1HeadWatcher(wsProvider string, logger log.Logger) *Eth1HeadWatcher {
+    return &Eth1HeadWatcher{
+        wsProvider: wsProvider,
+        log: logger,
+        handlers: []func(header *types.Header){},
+    }
+}
+
+
+// Add a new header handler
+func (w *Eth1HeadWatcher) OnNewHead(handler func(header *types.Header)) {
+    w.lock.Lock()
+    defer w.lock.Unlock()
+    w.handlers = append(w.handlers, handler)
+}
+
+
+// Start watching for new headers
+func (w *Eth1HeadWatcher) Start() {
+
+    // Cancel if no websocket provider is configured
+    if w.wsProvider == "" {
+        w.log.Warn("No Eth 1.0 websocket provider configured, tasks will run on polling intervals only.")
+        return
+    }
+
+    // Watch for new headers
+    go (func() {
+        for {
+            if err := w.watch(); err != nil {
+                w.log.Warn("Eth 1.0 header subscription dropped, falling back to polling until reconnected", "error", err, "retryIn", eth1ReconnectInterval)
+            }
+            time.Sleep(eth1ReconnectInterval)
+        }
+    })()
+
+}
+
+
+// Subscribe to new headers and dispatch them to handlers until the subscription fails
+func (w *Eth1HeadWatcher) watch() error {
+
+    // Connect to websocket provider
+    ec, err := ethclient.Dial(w.wsProvider)
+    if err != nil {
+        return fmt.Errorf("Could not connect to Eth 1.0 websocket provider: %w", err)
+    }
+    defer ec.Close()
+
+    // Subscribe to new headers
+    headers := make(chan *types.Header)
+    sub, err := ec.SubscribeNewHead(context.Background(), headers)
+    if err != nil {
+        return fmt.Errorf("Could not subscribe to Eth 1.0 headers: %w", err)
+    }
+    defer sub.Unsubscribe()
+
+    // Log
+    w.log.Info("Subscribed to Eth 1.0 headers.")
+
+    // Dispatch headers
+    for {
+        select {
+            case err := <-sub.Err():
+                return err
+            case header := <-headers:
+                w.log.Debug("Received Eth 1.0 header", "block", header.Number)
+                w.lock.Lock()
+                handlers := w.handlers
+                w.lock.Unlock()
+                for _, handler := range handlers {
+                    handler(header)
+                }
+        }
+    }
+
+}
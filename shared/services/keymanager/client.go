@@ -0,0 +1,332 @@
+package keymanager
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io/ioutil"
+    "net/http"
+    "strings"
+    "time"
+
+    "github.com/google/uuid"
+    rptypes "github.com/rocket-pool/rocketpool-go/types"
+    eth2types "github.com/wealdtech/go-eth2-types/v2"
+    eth2ks "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+)
+
+
+// Config
+const (
+    RequestContentType = "application/json"
+    RequestTimeout = 2 * time.Minute
+    KeystoresPath = "/eth/v1/keystores"
+    RemoteKeysPath = "/eth/v1/remotekeys"
+)
+
+
+// Keystore import statuses
+const (
+    StatusImported = "imported"
+    StatusDuplicate = "duplicate"
+    StatusError = "error"
+)
+
+
+// Keystore deletion statuses
+const (
+    StatusDeleted = "deleted"
+    StatusNotActive = "not_active"
+    StatusNotFound = "not_found"
+)
+
+
+// Validator client key manager API client
+// Uses the standard Eth2 key manager API to load validator keys into a running validator client or remote signer
+type Client struct {
+    url string
+    tokenPath string
+    encryptor *eth2ks.Encryptor
+    httpClient *http.Client
+}
+
+
+// Request / response types
+type importKeystoresRequest struct {
+    Keystores []string              `json:"keystores"`
+    Passwords []string              `json:"passwords"`
+}
+type importKeystoresResponse struct {
+    Data []ImportStatus             `json:"data"`
+}
+type ImportStatus struct {
+    Status string                   `json:"status"`
+    Message string                  `json:"message"`
+}
+type deleteKeystoresRequest struct {
+    Pubkeys []rptypes.ValidatorPubkey `json:"pubkeys"`
+}
+type deleteKeystoresResponse struct {
+    Data []DeleteStatus             `json:"data"`
+}
+type DeleteStatus struct {
+    Status string                   `json:"status"`
+    Message string                  `json:"message"`
+}
+type listKeystoresResponse struct {
+    Data []struct {
+        ValidatingPubkey rptypes.ValidatorPubkey `json:"validating_pubkey"`
+    }                               `json:"data"`
+}
+type importRemoteKeysRequest struct {
+    RemoteKeys []remoteKey          `json:"remote_keys"`
+}
+type remoteKey struct {
+    Pubkey rptypes.ValidatorPubkey  `json:"pubkey"`
+    Url string                      `json:"url"`
+}
+
+
+// Encrypted validator keystore
+type keystore struct {
+    Crypto map[string]interface{}   `json:"crypto"`
+    Version uint                    `json:"version"`
+    UUID uuid.UUID                  `json:"uuid"`
+    Path string                     `json:"path"`
+    Pubkey rptypes.ValidatorPubkey  `json:"pubkey"`
+}
+
+
+// Create new key manager API client
+func NewClient(url string, tokenPath string) *Client {
+    return &Client{
+        url: strings.TrimSuffix(url, "/"),
+        tokenPath: tokenPath,
+        encryptor: eth2ks.New(eth2ks.WithCipher("scrypt")),
+        httpClient: &http.Client{Timeout: RequestTimeout},
+    }
+}
+
+
+// Import validator keys into the validator client
+// Returns the import status for each key, in order
+func (c *Client) ImportKeys(keys []*eth2types.BLSPrivateKey, password string) ([]ImportStatus, error) {
+
+    // Encrypt keys
+    request := importKeystoresRequest{
+        Keystores: make([]string, len(keys)),
+        Passwords: make([]string, len(keys)),
+    }
+    for ki, key := range keys {
+        encryptedKey, err := c.encryptor.Encrypt(key.Marshal(), password)
+        if err != nil {
+            return []ImportStatus{}, fmt.Errorf("Could not encrypt validator key: %w", err)
+        }
+        keystoreBytes, err := json.Marshal(keystore{
+            Crypto: encryptedKey,
+            Version: c.encryptor.Version(),
+            UUID: uuid.New(),
+            Pubkey: rptypes.BytesToValidatorPubkey(key.PublicKey().Marshal()),
+        })
+        if err != nil {
+            return []ImportStatus{}, fmt.Errorf("Could not encode validator keystore: %w", err)
+        }
+        request.Keystores[ki] = string(keystoreBytes)
+        request.Passwords[ki] = password
+    }
+
+    // Send request
+    responseBody, status, err := c.postRequest(KeystoresPath, request)
+    if err != nil {
+        return []ImportStatus{}, fmt.Errorf("Could not import validator keystores: %w", err)
+    }
+    if status != http.StatusOK {
+        return []ImportStatus{}, fmt.Errorf("Could not import validator keystores: HTTP status %d; response body: '%s'", status, string(responseBody))
+    }
+    var response importKeystoresResponse
+    if err := json.Unmarshal(responseBody, &response); err != nil {
+        return []ImportStatus{}, fmt.Errorf("Could not decode import keystores response: %w", err)
+    }
+    if len(response.Data) != len(keys) {
+        return []ImportStatus{}, fmt.Errorf("Import keystores response contained %d statuses for %d keys", len(response.Data), len(keys))
+    }
+
+    // Return
+    return response.Data, nil
+
+}
+
+
+// Get the public keys of all validator keys held by the key manager
+func (c *Client) ListKeys() ([]rptypes.ValidatorPubkey, error) {
+
+    // Send request
+    responseBody, status, err := c.getRequest(KeystoresPath)
+    if err != nil {
+        return []rptypes.ValidatorPubkey{}, fmt.Errorf("Could not list validator keystores: %w", err)
+    }
+    if status != http.StatusOK {
+        return []rptypes.ValidatorPubkey{}, fmt.Errorf("Could not list validator keystores: HTTP status %d; response body: '%s'", status, string(responseBody))
+    }
+    var response listKeystoresResponse
+    if err := json.Unmarshal(responseBody, &response); err != nil {
+        return []rptypes.ValidatorPubkey{}, fmt.Errorf("Could not decode list keystores response: %w", err)
+    }
+
+    // Return
+    pubkeys := make([]rptypes.ValidatorPubkey, len(response.Data))
+    for ki, key := range response.Data {
+        pubkeys[ki] = key.ValidatingPubkey
+    }
+    return pubkeys, nil
+
+}
+
+
+// Delete validator keys from the key manager
+// Returns the deletion status for each key, in order
+func (c *Client) DeleteKeys(pubkeys []rptypes.ValidatorPubkey) ([]DeleteStatus, error) {
+
+    // Send request
+    responseBody, status, err := c.sendJSONRequest(http.MethodDelete, KeystoresPath, deleteKeystoresRequest{Pubkeys: pubkeys})
+    if err != nil {
+        return []DeleteStatus{}, fmt.Errorf("Could not delete validator keystores: %w", err)
+    }
+    if status != http.StatusOK {
+        return []DeleteStatus{}, fmt.Errorf("Could not delete validator keystores: HTTP status %d; response body: '%s'", status, string(responseBody))
+    }
+    var response deleteKeystoresResponse
+    if err := json.Unmarshal(responseBody, &response); err != nil {
+        return []DeleteStatus{}, fmt.Errorf("Could not decode delete keystores response: %w", err)
+    }
+    if len(response.Data) != len(pubkeys) {
+        return []DeleteStatus{}, fmt.Errorf("Delete keystores response contained %d statuses for %d keys", len(response.Data), len(pubkeys))
+    }
+
+    // Return
+    return response.Data, nil
+
+}
+
+
+// Import validator public keys into the validator client as remote keys, signed for by a remote signer
+// Returns the import status for each key, in order
+func (c *Client) ImportRemoteKeys(pubkeys []rptypes.ValidatorPubkey, signerUrl string) ([]ImportStatus, error) {
+
+    // Build request
+    request := importRemoteKeysRequest{
+        RemoteKeys: make([]remoteKey, len(pubkeys)),
+    }
+    for ki, pubkey := range pubkeys {
+        request.RemoteKeys[ki] = remoteKey{
+            Pubkey: pubkey,
+            Url: signerUrl,
+        }
+    }
+
+    // Send request
+    responseBody, status, err := c.postRequest(RemoteKeysPath, request)
+    if err != nil {
+        return []ImportStatus{}, fmt.Errorf("Could not import remote validator keys: %w", err)
+    }
+    if status != http.StatusOK {
+        return []ImportStatus{}, fmt.Errorf("Could not import remote validator keys: HTTP status %d; response body: '%s'", status, string(responseBody))
+    }
+    var response importKeystoresResponse
+    if err := json.Unmarshal(responseBody, &response); err != nil {
+        return []ImportStatus{}, fmt.Errorf("Could not decode import remote keys response: %w", err)
+    }
+    if len(response.Data) != len(pubkeys) {
+        return []ImportStatus{}, fmt.Errorf("Import remote keys response contained %d statuses for %d keys", len(response.Data), len(pubkeys))
+    }
+
+    // Return
+    return response.Data, nil
+
+}
+
+
+// Make an authorized GET request to the key manager API
+func (c *Client) getRequest(requestPath string) ([]byte, int, error) {
+
+    // Create request
+    request, err := http.NewRequest(http.MethodGet, c.url + requestPath, nil)
+    if err != nil {
+        return []byte{}, 0, err
+    }
+    if err := c.authorize(request); err != nil {
+        return []byte{}, 0, err
+    }
+
+    // Send request
+    return c.sendRequest(request)
+
+}
+
+
+// Make an authorized POST request to the key manager API
+func (c *Client) postRequest(requestPath string, requestBody interface{}) ([]byte, int, error) {
+    return c.sendJSONRequest(http.MethodPost, requestPath, requestBody)
+}
+
+
+// Make an authorized request with a JSON body to the key manager API
+func (c *Client) sendJSONRequest(method string, requestPath string, requestBody interface{}) ([]byte, int, error) {
+
+    // Get request body
+    requestBodyBytes, err := json.Marshal(requestBody)
+    if err != nil {
+        return []byte{}, 0, err
+    }
+
+    // Create request
+    request, err := http.NewRequest(method, c.url + requestPath, bytes.NewReader(requestBodyBytes))
+    if err != nil {
+        return []byte{}, 0, err
+    }
+    request.Header.Set("Content-Type", RequestContentType)
+    if err := c.authorize(request); err != nil {
+        return []byte{}, 0, err
+    }
+
+    // Send request
+    return c.sendRequest(request)
+
+}
+
+
+// Add the key manager API auth token to a request
+// Requests are sent without authorization if no token path is set
+func (c *Client) authorize(request *http.Request) error {
+    if c.tokenPath == "" {
+        return nil
+    }
+    token, err := ioutil.ReadFile(c.tokenPath)
+    if err != nil {
+        return fmt.Errorf("Could not read key manager API token: %w", err)
+    }
+    request.Header.Set("Authorization", "Bearer " + strings.TrimSpace(string(token)))
+    return nil
+}
+
+
+// Send a request to the key manager API and return the response body & status code
+func (c *Client) sendRequest(request *http.Request) ([]byte, int, error) {
+
+    // Send request
+    response, err := c.httpClient.Do(request)
+    if err != nil {
+        return []byte{}, 0, err
+    }
+    defer response.Body.Close()
+
+    // Get response
+    body, err := ioutil.ReadAll(response.Body)
+    if err != nil {
+        return []byte{}, 0, err
+    }
+
+    // Return
+    return body, response.StatusCode, nil
+
+}
@@ -0,0 +1,17 @@
+package services
+
+import (
+    "github.com/urfave/cli"
+
+    "github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+
+// Configure the log level & output format from the merged config
+func ConfigureLogger(c *cli.Context) error {
+    cfg, err := getConfig(c)
+    if err != nil {
+        return err
+    }
+    return log.Configure(cfg.Smartnode.LogLevel, cfg.Smartnode.LogFormat)
+}
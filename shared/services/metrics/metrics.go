@@ -0,0 +1,189 @@
+package metrics
+
+import (
+    "fmt"
+    "net/http"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/promauto"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
+    "github.com/rocket-pool/rocketpool-go/types"
+
+    "github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+
+// Config
+const (
+    Namespace = "rocketpool"
+    MetricsPath = "/metrics"
+)
+
+
+// Task metrics
+var (
+    TaskRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+        Namespace: Namespace,
+        Name: "task_runs_total",
+        Help: "Number of daemon task runs",
+    }, []string{"task"})
+    TaskErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+        Namespace: Namespace,
+        Name: "task_errors_total",
+        Help: "Number of daemon task runs which returned an error",
+    }, []string{"task"})
+    TaskDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+        Namespace: Namespace,
+        Name: "task_duration_seconds",
+        Help: "Duration of daemon task runs",
+        Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200},
+    }, []string{"task"})
+)
+
+
+// Transaction metrics
+var (
+    TransactionsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+        Namespace: Namespace,
+        Name: "transactions_sent_total",
+        Help: "Number of transactions sent successfully",
+    }, []string{"task"})
+    TransactionsFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+        Namespace: Namespace,
+        Name: "transactions_failed_total",
+        Help: "Number of transactions which failed to send or were reverted",
+    }, []string{"task"})
+)
+
+
+// Watchtower metrics
+var (
+    NetworkBalancesSubmittedBlock = promauto.NewGauge(prometheus.GaugeOpts{
+        Namespace: Namespace,
+        Name: "network_balances_submitted_block",
+        Help: "Block number of the last network balances successfully submitted by the node",
+    })
+    NetworkBalancesRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+        Namespace: Namespace,
+        Name: "network_balances_rejected_total",
+        Help: "Number of network balances submissions rejected by pre-submission checks, by reason",
+    }, []string{"reason"})
+    NetworkMinipools = promauto.NewGaugeVec(prometheus.GaugeOpts{
+        Namespace: Namespace,
+        Name: "network_minipools",
+        Help: "Number of minipools in the network by status",
+    }, []string{"status"})
+)
+
+
+// Node metrics
+var (
+    NodeMinipools = promauto.NewGaugeVec(prometheus.GaugeOpts{
+        Namespace: Namespace,
+        Name: "node_minipools",
+        Help: "Number of minipools owned by the node by status",
+    }, []string{"status"})
+    NodeBalance = promauto.NewGaugeVec(prometheus.GaugeOpts{
+        Namespace: Namespace,
+        Name: "node_balance",
+        Help: "Node account balance by token, in whole units",
+    }, []string{"token"})
+    NodeStakeChecksFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+        Namespace: Namespace,
+        Name: "node_stake_checks_failed_total",
+        Help: "Number of minipools skipped due to failed pre-stake safety checks, by check",
+    }, []string{"check"})
+)
+
+
+// Client sync metrics
+var (
+    Eth1Syncing = promauto.NewGauge(prometheus.GaugeOpts{
+        Namespace: Namespace,
+        Name: "eth1_syncing",
+        Help: "Whether the Eth 1.0 client is syncing (1) or synced (0)",
+    })
+    Eth1Block = promauto.NewGauge(prometheus.GaugeOpts{
+        Namespace: Namespace,
+        Name: "eth1_block",
+        Help: "Current Eth 1.0 block number",
+    })
+    BeaconSyncing = promauto.NewGauge(prometheus.GaugeOpts{
+        Namespace: Namespace,
+        Name: "beacon_syncing",
+        Help: "Whether the Eth 2.0 client is syncing (1) or synced (0)",
+    })
+    BeaconEpoch = promauto.NewGauge(prometheus.GaugeOpts{
+        Namespace: Namespace,
+        Name: "beacon_epoch",
+        Help: "Current Eth 2.0 epoch",
+    })
+    BeaconFinalizedEpoch = promauto.NewGauge(prometheus.GaugeOpts{
+        Namespace: Namespace,
+        Name: "beacon_finalized_epoch",
+        Help: "Current Eth 2.0 finalized epoch",
+    })
+)
+
+
+// Record a task run
+func RecordTaskRun(task string, duration time.Duration, failed bool) {
+    TaskRuns.WithLabelValues(task).Inc()
+    TaskDuration.WithLabelValues(task).Observe(duration.Seconds())
+    if failed {
+        TaskErrors.WithLabelValues(task).Inc()
+    }
+}
+
+
+// Record a transaction result
+func RecordTransaction(task string, err error) {
+    if err != nil {
+        TransactionsFailed.WithLabelValues(task).Inc()
+    } else {
+        TransactionsSent.WithLabelValues(task).Inc()
+    }
+}
+
+
+// Set minipool counts by status on a gauge
+func SetMinipoolCounts(gauge *prometheus.GaugeVec, statuses []types.MinipoolStatus) {
+    counts := make(map[types.MinipoolStatus]int)
+    for _, status := range statuses {
+        counts[status]++
+    }
+    for si, statusName := range types.MinipoolStatuses {
+        gauge.WithLabelValues(statusName).Set(float64(counts[types.MinipoolStatus(si)]))
+    }
+}
+
+
+// Convert a boolean to a gauge value
+func BoolToFloat(value bool) float64 {
+    if value {
+        return 1
+    }
+    return 0
+}
+
+
+// Start the metrics HTTP server if an address is set
+func Start(address string, logger log.Logger) {
+
+    // Cancel if metrics are disabled
+    if address == "" {
+        return
+    }
+
+    // Serve metrics
+    go (func() {
+        mux := http.NewServeMux()
+        mux.Handle(MetricsPath, promhttp.Handler())
+        logger.Info("Serving metrics", "url", fmt.Sprintf("http://%s%s", address, MetricsPath))
+        if err := http.ListenAndServe(address, mux); err != nil {
+            logger.Error("Could not serve metrics", "error", err)
+        }
+    })()
+
+}
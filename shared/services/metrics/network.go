@@ -0,0 +1,64 @@
+package metrics
+
+import (
+    "github.com/rocket-pool/rocketpool-go/minipool"
+    "github.com/rocket-pool/rocketpool-go/types"
+    "github.com/urfave/cli"
+    "golang.org/x/sync/errgroup"
+
+    "github.com/rocket-pool/smartnode/shared/services"
+)
+
+
+// Settings
+const NetworkMinipoolStatusBatchSize = 20
+
+
+// Update network minipool metrics
+func UpdateNetworkMetrics(c *cli.Context) error {
+
+    // Get services
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return err }
+
+    // Get minipool addresses
+    addresses, err := minipool.GetMinipoolAddresses(rp, nil)
+    if err != nil {
+        return err
+    }
+
+    // Load minipool statuses in batches
+    statuses := make([]types.MinipoolStatus, len(addresses))
+    for bsi := 0; bsi < len(addresses); bsi += NetworkMinipoolStatusBatchSize {
+
+        // Get batch start & end index
+        msi := bsi
+        mei := bsi + NetworkMinipoolStatusBatchSize
+        if mei > len(addresses) { mei = len(addresses) }
+
+        // Load statuses
+        var wg errgroup.Group
+        for mi := msi; mi < mei; mi++ {
+            mi := mi
+            wg.Go(func() error {
+                mp, err := minipool.NewMinipool(rp, addresses[mi])
+                if err != nil {
+                    return err
+                }
+                status, err := mp.GetStatus(nil)
+                if err == nil { statuses[mi] = status }
+                return err
+            })
+        }
+        if err := wg.Wait(); err != nil {
+            return err
+        }
+
+    }
+
+    // Update network minipool counts
+    SetMinipoolCounts(NetworkMinipools, statuses)
+    return nil
+
+}
+
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+    "context"
+
+    "github.com/rocket-pool/rocketpool-go/minipool"
+    "github.com/rocket-pool/rocketpool-go/tokens"
+    "github.com/rocket-pool/rocketpool-go/types"
+    "github.com/rocket-pool/rocketpool-go/utils/eth"
+    "github.com/urfave/cli"
+    "golang.org/x/sync/errgroup"
+
+    "github.com/rocket-pool/smartnode/shared/services"
+)
+
+
+// Update node balance, minipool & client sync metrics
+func UpdateNodeMetrics(c *cli.Context) error {
+
+    // Get services
+    w, err := services.GetWallet(c)
+    if err != nil { return err }
+    ec, err := services.GetEthClient(c)
+    if err != nil { return err }
+    rp, err := services.GetRocketPool(c)
+    if err != nil { return err }
+    bc, err := services.GetBeaconClient(c)
+    if err != nil { return err }
+
+    // Get node account
+    nodeAccount, err := w.GetNodeAccount()
+    if err != nil {
+        return err
+    }
+
+    // Sync
+    var wg errgroup.Group
+
+    // Update eth1 sync status
+    wg.Go(func() error {
+        progress, err := ec.SyncProgress(context.Background())
+        if err != nil {
+            return err
+        }
+        Eth1Syncing.Set(BoolToFloat(progress != nil))
+        header, err := ec.HeaderByNumber(context.Background(), nil)
+        if err != nil {
+            return err
+        }
+        Eth1Block.Set(float64(header.Number.Uint64()))
+        return nil
+    })
+
+    // Update beacon sync status
+    wg.Go(func() error {
+        syncStatus, err := bc.GetSyncStatus()
+        if err != nil {
+            return err
+        }
+        BeaconSyncing.Set(BoolToFloat(syncStatus.Syncing))
+        head, err := bc.GetBeaconHead()
+        if err != nil {
+            return err
+        }
+        BeaconEpoch.Set(float64(head.Epoch))
+        BeaconFinalizedEpoch.Set(float64(head.FinalizedEpoch))
+        return nil
+    })
+
+    // Update node balances
+    wg.Go(func() error {
+        balances, err := tokens.GetBalances(rp, nodeAccount.Address, nil)
+        if err != nil {
+            return err
+        }
+        NodeBalance.WithLabelValues("eth").Set(eth.WeiToEth(balances.ETH))
+        NodeBalance.WithLabelValues("neth").Set(eth.WeiToEth(balances.NETH))
+        NodeBalance.WithLabelValues("reth").Set(eth.WeiToEth(balances.RETH))
+        return nil
+    })
+
+    // Update node minipool counts
+    wg.Go(func() error {
+        addresses, err := minipool.GetNodeMinipoolAddresses(rp, nodeAccount.Address, nil)
+        if err != nil {
+            return err
+        }
+        statuses := make([]types.MinipoolStatus, len(addresses))
+        var wg2 errgroup.Group
+        for mi, address := range addresses {
+            mi, address := mi, address
+            wg2.Go(func() error {
+                mp, err := minipool.NewMinipool(rp, address)
+                if err != nil {
+                    return err
+                }
+                status, err := mp.GetStatus(nil)
+                if err == nil { statuses[mi] = status }
+                return err
+            })
+        }
+        if err := wg2.Wait(); err != nil {
+            return err
+        }
+        SetMinipoolCounts(NodeMinipools, statuses)
+        return nil
+    })
+
+    // Wait for data
+    return wg.Wait()
+
+}
+
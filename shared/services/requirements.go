@@ -3,13 +3,16 @@ package services
 import (
     "context"
     "errors"
-    "log"
+    "fmt"
     "sync"
     "time"
 
     "github.com/ethereum/go-ethereum/common"
+    "github.com/fatih/color"
     "github.com/rocket-pool/rocketpool-go/node"
     "github.com/urfave/cli"
+
+    "github.com/rocket-pool/smartnode/shared/utils/log"
 )
 
 
@@ -24,6 +27,10 @@ var ethClientSyncPollInterval, _ = time.ParseDuration("5s")
 var beaconClientSyncPollInterval, _ = time.ParseDuration("5s")
 
 
+// Logger for service synchronization messages
+var requirementsLog = log.NewLogger(color.Reset)
+
+
 //
 // Service requirements
 //
@@ -128,7 +135,7 @@ func WaitNodePassword(c *cli.Context, verbose bool) error {
             return nil
         }
         if verbose {
-            log.Printf("The node password has not been set, retrying in %s...\n", checkNodePasswordInterval.String())
+            requirementsLog.Warn("The node password has not been set, retrying...", "retryIn", checkNodePasswordInterval)
         }
         time.Sleep(checkNodePasswordInterval)
     }
@@ -148,7 +155,7 @@ func WaitNodeWallet(c *cli.Context, verbose bool) error {
             return nil
         }
         if verbose {
-            log.Printf("The node wallet has not been initialized, retrying in %s...\n", checkNodeWalletInterval.String())
+            requirementsLog.Warn("The node wallet has not been initialized, retrying...", "retryIn", checkNodeWalletInterval)
         }
         time.Sleep(checkNodeWalletInterval)
     }
@@ -180,7 +187,7 @@ func WaitRocketStorage(c *cli.Context, verbose bool) error {
             return nil
         }
         if verbose {
-            log.Printf("The Rocket Pool storage contract was not found, retrying in %s...\n", checkRocketStorageInterval.String())
+            requirementsLog.Warn("The Rocket Pool storage contract was not found, retrying...", "retryIn", checkRocketStorageInterval)
         }
         time.Sleep(checkRocketStorageInterval)
     }
@@ -203,7 +210,7 @@ func WaitNodeRegistered(c *cli.Context, verbose bool) error {
             return nil
         }
         if verbose {
-            log.Printf("The node is not registered with Rocket Pool, retrying in %s...\n", checkNodeRegisteredInterval.String())
+            requirementsLog.Warn("The node is not registered with Rocket Pool, retrying...", "retryIn", checkNodeRegisteredInterval)
         }
         time.Sleep(checkNodeRegisteredInterval)
     }
@@ -308,9 +315,9 @@ func waitEthClientSynced(c *cli.Context, verbose bool, timeout int64) (bool, err
             if verbose {
                 p := float64(progress.CurrentBlock - progress.StartingBlock) / float64(progress.HighestBlock - progress.StartingBlock)
                 if p > 1 {
-                    log.Println("Eth 1.0 node syncing...")
+                    requirementsLog.Info("Eth 1.0 node syncing...")
                 } else {
-                    log.Printf("Eth 1.0 node syncing: %.2f%%\n", p * 100)
+                    requirementsLog.Info("Eth 1.0 node syncing...", "progress", fmt.Sprintf("%.2f%%", p * 100))
                 }
             }
         } else {
@@ -360,7 +367,7 @@ func waitBeaconClientSynced(c *cli.Context, verbose bool, timeout int64) (bool,
         // Check sync status
         if syncStatus.Syncing {
             if verbose {
-                log.Println("Eth 2.0 node syncing...")
+                requirementsLog.Info("Eth 2.0 node syncing...")
             }
         } else {
             return true, nil
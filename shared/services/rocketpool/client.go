@@ -353,6 +353,7 @@ func (c *Client) compose(composeFiles []string, args string) (string, error) {
         fmt.Sprintf("ETH1_PROVIDER='%s'",           cfg.Chains.Eth1.Provider),
         fmt.Sprintf("ETH1_WS_PROVIDER='%s'",        cfg.Chains.Eth1.WsProvider),
         fmt.Sprintf("ETH2_PROVIDER='%s'",           cfg.Chains.Eth2.Provider),
+        fmt.Sprintf("REMOTE_SIGNER_URL='%s'",       cfg.GetRemoteSignerValidatorUrl()),
     }
     for _, param := range cfg.Chains.Eth1.Client.Params {
         env = append(env, fmt.Sprintf("%s='%s'", param.Env, param.Value))
@@ -376,17 +377,34 @@ func (c *Client) compose(composeFiles []string, args string) (string, error) {
 
 // Call the Rocket Pool API
 func (c *Client) callAPI(args string) ([]byte, error) {
-    var cmd string
+    cmd, err := c.getAPICommand(args)
+    if err != nil {
+        return []byte{}, err
+    }
+    return c.readOutput(cmd)
+}
+
+
+// Call a streaming Rocket Pool API command, passing each line of output to a handler
+func (c *Client) streamAPI(args string, handler func(line []byte) error) error {
+    cmd, err := c.getAPICommand(args)
+    if err != nil {
+        return err
+    }
+    return c.streamOutput(cmd, handler)
+}
+
+
+// Get the command text for a Rocket Pool API command
+func (c *Client) getAPICommand(args string) (string, error) {
     if c.daemonPath == "" {
         containerName, err := c.getAPIContainerName()
         if err != nil {
-            return []byte{}, err
+            return "", err
         }
-        cmd = fmt.Sprintf("docker exec %s %s %s api %s", containerName, APIBinPath, c.getGasOpts(), args)
-    } else {
-        cmd = fmt.Sprintf("%s --config %s --settings %s %s api %s", c.daemonPath, fmt.Sprintf("%s/%s", c.configPath, GlobalConfigFile), fmt.Sprintf("%s/%s", c.configPath, UserConfigFile), c.getGasOpts(), args)
+        return fmt.Sprintf("docker exec %s %s %s api %s", containerName, APIBinPath, c.getGasOpts(), args), nil
     }
-    return c.readOutput(cmd)
+    return fmt.Sprintf("%s --config %s --settings %s %s api %s", c.daemonPath, fmt.Sprintf("%s/%s", c.configPath, GlobalConfigFile), fmt.Sprintf("%s/%s", c.configPath, UserConfigFile), c.getGasOpts(), args), nil
 }
 
 
@@ -474,3 +492,61 @@ func (c *Client) readOutput(cmdText string) ([]byte, error) {
 
 }
 
+
+// Run a command and write input to its stdin
+func (c *Client) writeInput(cmdText string, input []byte) error {
+
+    // Initialize command
+    cmd, err := c.newCommand(cmdText)
+    if err != nil { return err }
+    defer cmd.Close()
+
+    // Start command
+    cmdIn, err := cmd.StdinPipe()
+    if err != nil { return err }
+    if err := cmd.Start(); err != nil { return err }
+
+    // Write input
+    if _, err := cmdIn.Write(input); err != nil {
+        cmdIn.Close()
+        return err
+    }
+    if err := cmdIn.Close(); err != nil {
+        return err
+    }
+
+    // Wait for command to exit
+    return cmd.Wait()
+
+}
+
+
+// Run a command and pass each line of its output to a handler until it exits
+func (c *Client) streamOutput(cmdText string, handler func(line []byte) error) error {
+
+    // Initialize command
+    cmd, err := c.newCommand(cmdText)
+    if err != nil { return err }
+    defer cmd.Close()
+
+    // Start command
+    cmdOut, err := cmd.StdoutPipe()
+    if err != nil { return err }
+    if err := cmd.Start(); err != nil { return err }
+
+    // Handle output lines
+    scanner := bufio.NewScanner(cmdOut)
+    for scanner.Scan() {
+        if err := handler(scanner.Bytes()); err != nil {
+            return err
+        }
+    }
+    if err := scanner.Err(); err != nil {
+        return err
+    }
+
+    // Wait for command to exit
+    return cmd.Wait()
+
+}
+
@@ -55,6 +55,26 @@ func (c *command) Run() error {
 }
 
 
+// Start the command without waiting for it to complete
+func (c *command) Start() error {
+    if c.cmd != nil {
+        return c.cmd.Start()
+    } else {
+        return c.session.Start(c.cmdText)
+    }
+}
+
+
+// Wait for a started command to complete
+func (c *command) Wait() error {
+    if c.cmd != nil {
+        return c.cmd.Wait()
+    } else {
+        return c.session.Wait()
+    }
+}
+
+
 // Run the command and return its output
 func (c *command) Output() ([]byte, error) {
     if c.cmd != nil {
@@ -65,6 +85,16 @@ func (c *command) Output() ([]byte, error) {
 }
 
 
+// Get a pipe to the command's stdin
+func (c *command) StdinPipe() (io.WriteCloser, error) {
+    if c.cmd != nil {
+        return c.cmd.StdinPipe()
+    } else {
+        return c.session.StdinPipe()
+    }
+}
+
+
 // Get a pipe to the command's stdout
 func (c *command) StdoutPipe() (io.Reader, error) {
     if c.cmd != nil {
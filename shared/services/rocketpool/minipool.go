@@ -27,6 +27,23 @@ func (c *Client) MinipoolStatus() (api.MinipoolStatusResponse, error) {
 }
 
 
+// Get the duty performance and balance change of the node's minipools over recent epochs
+func (c *Client) MinipoolPerformance(epochs uint64) (api.MinipoolPerformanceResponse, error) {
+    responseBytes, err := c.callAPI(fmt.Sprintf("minipool performance %d", epochs))
+    if err != nil {
+        return api.MinipoolPerformanceResponse{}, fmt.Errorf("Could not get minipool performance: %w", err)
+    }
+    var response api.MinipoolPerformanceResponse
+    if err := json.Unmarshal(responseBytes, &response); err != nil {
+        return api.MinipoolPerformanceResponse{}, fmt.Errorf("Could not decode minipool performance response: %w", err)
+    }
+    if response.Error != "" {
+        return api.MinipoolPerformanceResponse{}, fmt.Errorf("Could not get minipool performance: %s", response.Error)
+    }
+    return response, nil
+}
+
+
 // Check whether a minipool is eligible for a refund
 func (c *Client) CanRefundMinipool(address common.Address) (api.CanRefundMinipoolResponse, error) {
     responseBytes, err := c.callAPI(fmt.Sprintf("minipool can-refund %s", address.Hex()))
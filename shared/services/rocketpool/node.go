@@ -28,6 +28,22 @@ func (c *Client) NodeStatus() (api.NodeStatusResponse, error) {
 }
 
 
+// Stream beacon chain events relevant to the node's validators until the stream fails
+func (c *Client) StreamNodeEvents(handler func(event api.NodeEventResponse)) error {
+    return c.streamAPI("node events", func(line []byte) error {
+        var response api.NodeEventResponse
+        if err := json.Unmarshal(line, &response); err != nil {
+            return fmt.Errorf("Could not decode node event: %w", err)
+        }
+        if response.Error != "" {
+            return fmt.Errorf("Could not stream node events: %s", response.Error)
+        }
+        handler(response)
+        return nil
+    })
+}
+
+
 // Check whether the node can be registered
 func (c *Client) CanRegisterNode() (api.CanRegisterNodeResponse, error) {
     responseBytes, err := c.callAPI("node can-register")
@@ -0,0 +1,153 @@
+package rocketpool
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+
+    "github.com/rocket-pool/smartnode/shared/services/config"
+)
+
+
+// Config
+const InterchangeDir = "/interchange"
+
+
+// Export the selected Eth 2.0 client's slashing protection history in EIP-3076 interchange format
+// The client's validator service is stopped during the export, and only restarted afterwards if restart is set
+func (c *Client) ExportSlashingProtection(composeFiles []string, restart bool) ([]byte, error) {
+
+    // Get interchange tool
+    cfg, tool, err := c.getInterchangeTool()
+    if err != nil {
+        return []byte{}, err
+    }
+
+    // Create interchange folder
+    dir, err := c.makeInterchangeDir()
+    if err != nil {
+        return []byte{}, err
+    }
+    defer c.readOutput(fmt.Sprintf("rm -rf %s", dir))
+
+    // Run export
+    if err := c.runInterchangeTool(composeFiles, cfg, tool, tool.ExportArgs, dir, restart); err != nil {
+        return []byte{}, fmt.Errorf("Could not export slashing protection history: %w", err)
+    }
+
+    // Read interchange file
+    interchange, err := c.readOutput(fmt.Sprintf("cat %s/%s", dir, tool.ExportFile))
+    if err != nil {
+        return []byte{}, fmt.Errorf("Could not read exported slashing protection history: %w", err)
+    }
+    return interchange, nil
+
+}
+
+
+// Import EIP-3076 slashing protection history into the selected Eth 2.0 client
+// The client's validator service is stopped during the import, and only restarted afterwards if restart is set
+func (c *Client) ImportSlashingProtection(composeFiles []string, interchange []byte, restart bool) error {
+
+    // Get interchange tool
+    cfg, tool, err := c.getInterchangeTool()
+    if err != nil {
+        return err
+    }
+
+    // Create interchange folder
+    dir, err := c.makeInterchangeDir()
+    if err != nil {
+        return err
+    }
+    defer c.readOutput(fmt.Sprintf("rm -rf %s", dir))
+
+    // Write interchange file
+    if err := c.writeInput(fmt.Sprintf("cat > %s/%s", dir, tool.ExportFile), interchange); err != nil {
+        return fmt.Errorf("Could not write slashing protection history: %w", err)
+    }
+
+    // Run import
+    if err := c.runInterchangeTool(composeFiles, cfg, tool, tool.ImportArgs, dir, restart); err != nil {
+        return fmt.Errorf("Could not import slashing protection history: %w", err)
+    }
+    return nil
+
+}
+
+
+// Get the selected Eth 2.0 client's slashing protection interchange tool
+func (c *Client) getInterchangeTool() (config.RocketPoolConfig, config.InterchangeTool, error) {
+    cfg, err := c.LoadMergedConfig()
+    if err != nil {
+        return config.RocketPoolConfig{}, config.InterchangeTool{}, err
+    }
+    client := cfg.GetSelectedEth2Client()
+    if client == nil {
+        return config.RocketPoolConfig{}, config.InterchangeTool{}, errors.New("No Eth 2.0 client selected. Please run 'rocketpool service config' and try again.")
+    }
+    tool, err := client.GetInterchangeTool()
+    if err != nil {
+        return config.RocketPoolConfig{}, config.InterchangeTool{}, err
+    }
+    return cfg, tool, nil
+}
+
+
+// Create a temporary folder to share interchange files with the interchange tool container
+func (c *Client) makeInterchangeDir() (string, error) {
+    dirBytes, err := c.readOutput("mktemp -d")
+    if err != nil {
+        return "", fmt.Errorf("Could not create slashing protection interchange folder: %w", err)
+    }
+    dir := strings.TrimSpace(string(dirBytes))
+    if _, err := c.readOutput(fmt.Sprintf("chmod 777 %s", dir)); err != nil {
+        return "", fmt.Errorf("Could not set slashing protection interchange folder permissions: %w", err)
+    }
+    return dir, nil
+}
+
+
+// Run a slashing protection interchange tool command in a one-off container for its service
+func (c *Client) runInterchangeTool(composeFiles []string, cfg config.RocketPoolConfig, tool config.InterchangeTool, toolArgs []string, dir string, restart bool) error {
+
+    // Get tool arguments
+    replacer := strings.NewReplacer(
+        "{file}", fmt.Sprintf("%s/%s", InterchangeDir, tool.ExportFile),
+        "{dir}", InterchangeDir,
+        "{network}", cfg.Chains.Eth2.Network,
+        "{provider}", cfg.Chains.Eth2.Provider)
+    args := make([]string, len(toolArgs))
+    for ai, arg := range toolArgs {
+        if strings.Contains(arg, "{network}") && cfg.Chains.Eth2.Network == "" {
+            return errors.New("The Eth 2.0 network is not set in the Rocket Pool config")
+        }
+        args[ai] = fmt.Sprintf("'%s'", replacer.Replace(arg))
+    }
+
+    // Stop the validator service so that it cannot sign while its history is in use
+    stopCmd, err := c.compose(composeFiles, fmt.Sprintf("stop %s", tool.Service))
+    if err != nil { return err }
+    if err := c.printOutput(stopCmd); err != nil {
+        return fmt.Errorf("Could not stop the %s service: %w", tool.Service, err)
+    }
+
+    // Run tool
+    runCmd, err := c.compose(composeFiles, fmt.Sprintf("run --rm --no-deps -v %s:%s --entrypoint '%s' %s %s", dir, InterchangeDir, tool.Entrypoint, tool.Service, strings.Join(args, " ")))
+    if err != nil { return err }
+    runErr := c.printOutput(runCmd)
+
+    // Restart the validator service
+    if restart {
+        startCmd, err := c.compose(composeFiles, fmt.Sprintf("start %s", tool.Service))
+        if err != nil { return err }
+        if err := c.printOutput(startCmd); err != nil {
+            return fmt.Errorf("Could not restart the %s service: %w", tool.Service, err)
+        }
+    }
+
+    // Return
+    return runErr
+
+}
+
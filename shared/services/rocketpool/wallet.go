@@ -3,6 +3,9 @@ package rocketpool
 import (
     "encoding/json"
     "fmt"
+    "strings"
+
+    rptypes "github.com/rocket-pool/rocketpool-go/types"
 
     "github.com/rocket-pool/smartnode/shared/types/api"
 )
@@ -60,8 +63,8 @@ func (c *Client) InitWallet() (api.InitWalletResponse, error) {
 
 
 // Recover wallet
-func (c *Client) RecoverWallet(mnemonic string) (api.RecoverWalletResponse, error) {
-    responseBytes, err := c.callAPI(fmt.Sprintf("wallet recover \"%s\"", mnemonic))
+func (c *Client) RecoverWallet(mnemonic string, searchWindow uint64) (api.RecoverWalletResponse, error) {
+    responseBytes, err := c.callAPI(fmt.Sprintf("wallet recover \"%s\" %d", mnemonic, searchWindow))
     if err != nil {
         return api.RecoverWalletResponse{}, fmt.Errorf("Could not recover wallet: %w", err)
     }
@@ -93,6 +96,78 @@ func (c *Client) RebuildWallet() (api.RebuildWalletResponse, error) {
 }
 
 
+// Get stored validator keys which can be pruned
+func (c *Client) GetPrunableValidatorKeys() (api.GetPrunableValidatorKeysResponse, error) {
+    responseBytes, err := c.callAPI("wallet get-prunable-keys")
+    if err != nil {
+        return api.GetPrunableValidatorKeysResponse{}, fmt.Errorf("Could not get prunable validator keys: %w", err)
+    }
+    var response api.GetPrunableValidatorKeysResponse
+    if err := json.Unmarshal(responseBytes, &response); err != nil {
+        return api.GetPrunableValidatorKeysResponse{}, fmt.Errorf("Could not decode prunable validator keys response: %w", err)
+    }
+    if response.Error != "" {
+        return api.GetPrunableValidatorKeysResponse{}, fmt.Errorf("Could not get prunable validator keys: %s", response.Error)
+    }
+    return response, nil
+}
+
+
+// Prune wallet; only the given validator keys are deleted, if still prunable
+func (c *Client) PruneWallet(pubkeys []rptypes.ValidatorPubkey) (api.PruneWalletResponse, error) {
+    pubkeysHex := make([]string, len(pubkeys))
+    for pi, pubkey := range pubkeys {
+        pubkeysHex[pi] = pubkey.Hex()
+    }
+    responseBytes, err := c.callAPI(fmt.Sprintf("wallet prune %s", strings.Join(pubkeysHex, ",")))
+    if err != nil {
+        return api.PruneWalletResponse{}, fmt.Errorf("Could not prune wallet: %w", err)
+    }
+    var response api.PruneWalletResponse
+    if err := json.Unmarshal(responseBytes, &response); err != nil {
+        return api.PruneWalletResponse{}, fmt.Errorf("Could not decode prune wallet response: %w", err)
+    }
+    if response.Error != "" {
+        return api.PruneWalletResponse{}, fmt.Errorf("Could not prune wallet: %s", response.Error)
+    }
+    return response, nil
+}
+
+
+// Audit wallet
+func (c *Client) AuditWallet() (api.AuditWalletResponse, error) {
+    responseBytes, err := c.callAPI("wallet audit")
+    if err != nil {
+        return api.AuditWalletResponse{}, fmt.Errorf("Could not audit wallet: %w", err)
+    }
+    var response api.AuditWalletResponse
+    if err := json.Unmarshal(responseBytes, &response); err != nil {
+        return api.AuditWalletResponse{}, fmt.Errorf("Could not decode audit wallet response: %w", err)
+    }
+    if response.Error != "" {
+        return api.AuditWalletResponse{}, fmt.Errorf("Could not audit wallet: %s", response.Error)
+    }
+    return response, nil
+}
+
+
+// Repair wallet
+func (c *Client) RepairWallet() (api.RepairWalletResponse, error) {
+    responseBytes, err := c.callAPI("wallet repair")
+    if err != nil {
+        return api.RepairWalletResponse{}, fmt.Errorf("Could not repair wallet: %w", err)
+    }
+    var response api.RepairWalletResponse
+    if err := json.Unmarshal(responseBytes, &response); err != nil {
+        return api.RepairWalletResponse{}, fmt.Errorf("Could not decode repair wallet response: %w", err)
+    }
+    if response.Error != "" {
+        return api.RepairWalletResponse{}, fmt.Errorf("Could not repair wallet: %s", response.Error)
+    }
+    return response, nil
+}
+
+
 // Export wallet
 func (c *Client) ExportWallet() (api.ExportWalletResponse, error) {
     responseBytes, err := c.callAPI("wallet export")
@@ -4,6 +4,7 @@ import (
     "fmt"
     "math/big"
     "os"
+    "path/filepath"
     "sync"
 
     "github.com/docker/docker/client"
@@ -13,22 +14,28 @@ import (
     "github.com/urfave/cli"
 
     "github.com/rocket-pool/smartnode/shared/services/beacon"
+    "github.com/rocket-pool/smartnode/shared/services/beacon/cache"
+    "github.com/rocket-pool/smartnode/shared/services/beacon/failover"
     "github.com/rocket-pool/smartnode/shared/services/beacon/lighthouse"
     "github.com/rocket-pool/smartnode/shared/services/beacon/nimbus"
     "github.com/rocket-pool/smartnode/shared/services/beacon/prysm"
+    "github.com/rocket-pool/smartnode/shared/services/beacon/standard"
     "github.com/rocket-pool/smartnode/shared/services/beacon/teku"
     "github.com/rocket-pool/smartnode/shared/services/config"
+    "github.com/rocket-pool/smartnode/shared/services/database"
     "github.com/rocket-pool/smartnode/shared/services/passwords"
     "github.com/rocket-pool/smartnode/shared/services/wallet"
     lhkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/lighthouse"
     nmkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/nimbus"
     prkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/prysm"
+    rmkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/remote"
     tkkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/teku"
 )
 
 
 // Config
 const DockerAPIVersion = "1.40"
+const DefaultDatabaseDir = "data/database"
 
 
 // Service instances & initializers
@@ -40,6 +47,10 @@ var (
     rocketPool *rocketpool.RocketPool
     beaconClient beacon.Client
     docker *client.Client
+    db *database.Database
+    checkEthClient *ethclient.Client
+    checkRocketPool *rocketpool.RocketPool
+    checkBeaconClient beacon.Client
 
     initCfg sync.Once
     initPasswordManager sync.Once
@@ -48,6 +59,10 @@ var (
     initRocketPool sync.Once
     initBeaconClient sync.Once
     initDocker sync.Once
+    initDatabase sync.Once
+    initCheckEthClient sync.Once
+    initCheckRocketPool sync.Once
+    initCheckBeaconClient sync.Once
 )
 
 
@@ -116,6 +131,46 @@ func GetDocker(c *cli.Context) (*client.Client, error) {
 }
 
 
+func GetCheckEthClient(c *cli.Context) (*ethclient.Client, error) {
+    cfg, err := getConfig(c)
+    if err != nil {
+        return nil, err
+    }
+    return getCheckEthClient(cfg)
+}
+
+
+func GetCheckRocketPool(c *cli.Context) (*rocketpool.RocketPool, error) {
+    cfg, err := getConfig(c)
+    if err != nil {
+        return nil, err
+    }
+    ec, err := getCheckEthClient(cfg)
+    if err != nil || ec == nil {
+        return nil, err
+    }
+    return getCheckRocketPool(cfg, ec)
+}
+
+
+func GetCheckBeaconClient(c *cli.Context) (beacon.Client, error) {
+    cfg, err := getConfig(c)
+    if err != nil {
+        return nil, err
+    }
+    return getCheckBeaconClient(cfg)
+}
+
+
+func GetDatabase(c *cli.Context) (*database.Database, error) {
+    cfg, err := getConfig(c)
+    if err != nil {
+        return nil, err
+    }
+    return getDatabase(c, cfg), nil
+}
+
+
 //
 // Service instance getters
 //
@@ -149,6 +204,11 @@ func getWallet(cfg config.RocketPoolConfig, pm *passwords.PasswordManager) (*wal
         if err != nil { return }
         nodeWallet, err = wallet.NewWallet(os.ExpandEnv(cfg.Smartnode.WalletPath), cfg.Chains.Eth1.ChainID, gasPrice, gasLimit, pm)
         if err != nil { return }
+        if cfg.Smartnode.RemoteSigner.Url != "" {
+            remoteKeystore := rmkeystore.NewKeystore(cfg.Smartnode.RemoteSigner.Url, os.ExpandEnv(cfg.Smartnode.RemoteSigner.TokenPath), pm)
+            nodeWallet.AddKeystore("remote", remoteKeystore)
+            return
+        }
         lighthouseKeystore := lhkeystore.NewKeystore(os.ExpandEnv(cfg.Smartnode.ValidatorKeychainPath), pm)
         nimbusKeystore := nmkeystore.NewKeystore(os.ExpandEnv(cfg.Smartnode.ValidatorKeychainPath), pm)
         prysmKeystore := prkeystore.NewKeystore(os.ExpandEnv(cfg.Smartnode.ValidatorKeychainPath), pm)
@@ -183,23 +243,60 @@ func getRocketPool(cfg config.RocketPoolConfig, client *ethclient.Client) (*rock
 func getBeaconClient(cfg config.RocketPoolConfig) (beacon.Client, error) {
     var err error
     initBeaconClient.Do(func() {
-        switch cfg.Chains.Eth2.Client.Selected {
-            case "lighthouse":
-                beaconClient = lighthouse.NewClient(cfg.Chains.Eth2.Provider)
-            case "nimbus":
-                beaconClient, err = nimbus.NewClient(cfg.Chains.Eth2.Provider)
-            case "prysm":
-                beaconClient, err = prysm.NewClient(cfg.Chains.Eth2.Provider)
-            case "teku":
-                beaconClient = teku.NewClient(cfg.Chains.Eth2.Provider)
-            default:
-                err = fmt.Errorf("Unknown Eth 2.0 client '%s' selected", cfg.Chains.Eth2.Client.Selected)
+        providers := cfg.Chains.Eth2.GetProviders()
+        if len(providers) == 1 {
+            var client beacon.Client
+            if client, err = newBeaconClient(cfg.Chains.Eth2.Client.Selected, providers[0], cfg.Chains.Eth2.EventsProvider); err != nil {
+                return
+            }
+            beaconClient = cache.NewClient(client)
+            return
         }
+        clients := make([]beacon.Client, len(providers))
+        for pi, provider := range providers {
+            var eventsProvider string
+            if pi == 0 { eventsProvider = cfg.Chains.Eth2.EventsProvider }
+            if clients[pi], err = newBeaconClient(cfg.Chains.Eth2.Client.Selected, provider, eventsProvider); err != nil {
+                return
+            }
+        }
+        beaconClient = cache.NewClient(failover.NewClient(providers, clients))
     })
     return beaconClient, err
 }
 
 
+func getCheckEthClient(cfg config.RocketPoolConfig) (*ethclient.Client, error) {
+    var err error
+    initCheckEthClient.Do(func() {
+        if cfg.Chains.Eth1.CheckProvider != "" {
+            checkEthClient, err = ethclient.Dial(cfg.Chains.Eth1.CheckProvider)
+        }
+    })
+    return checkEthClient, err
+}
+
+
+func getCheckRocketPool(cfg config.RocketPoolConfig, client *ethclient.Client) (*rocketpool.RocketPool, error) {
+    var err error
+    initCheckRocketPool.Do(func() {
+        checkRocketPool, err = rocketpool.NewRocketPool(client, common.HexToAddress(cfg.Rocketpool.StorageAddress))
+    })
+    return checkRocketPool, err
+}
+
+
+func getCheckBeaconClient(cfg config.RocketPoolConfig) (beacon.Client, error) {
+    var err error
+    initCheckBeaconClient.Do(func() {
+        if cfg.Chains.Eth2.CheckProvider != "" {
+            checkBeaconClient, err = newBeaconClient(cfg.Chains.Eth2.Client.Selected, cfg.Chains.Eth2.CheckProvider, "")
+        }
+    })
+    return checkBeaconClient, err
+}
+
+
 func getDocker() (*client.Client, error) {
     var err error
     initDocker.Do(func() {
@@ -207,3 +304,33 @@ func getDocker() (*client.Client, error) {
     })
     return docker, err
 }
+
+
+func getDatabase(c *cli.Context, cfg config.RocketPoolConfig) *database.Database {
+    initDatabase.Do(func() {
+        path := os.ExpandEnv(cfg.Smartnode.DatabasePath)
+        if path == "" {
+            path = filepath.Join(filepath.Dir(os.ExpandEnv(c.GlobalString("config"))), DefaultDatabaseDir)
+        }
+        db = database.NewDatabase(path)
+    })
+    return db
+}
+
+
+func newBeaconClient(clientType string, provider string, eventsProvider string) (beacon.Client, error) {
+    switch clientType {
+        case "lighthouse":
+            return lighthouse.NewClient(provider), nil
+        case "nimbus":
+            return nimbus.NewClient(provider, eventsProvider)
+        case "prysm":
+            return prysm.NewClient(provider)
+        case "teku":
+            return teku.NewClient(provider), nil
+        case "standard":
+            return standard.NewClient(provider, standard.Quirks{ClientType: beacon.SplitProcess}), nil
+        default:
+            return nil, fmt.Errorf("Unknown Eth 2.0 client '%s' selected", clientType)
+    }
+}
@@ -0,0 +1,174 @@
+package wallet
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+
+    rptypes "github.com/rocket-pool/rocketpool-go/types"
+
+    "github.com/rocket-pool/smartnode/shared/services/wallet/keystore"
+)
+
+
+// Validator key audit statuses
+const (
+    KeyStatusOK = "ok"
+    KeyStatusMissing = "missing"
+    KeyStatusUndecryptable = "undecryptable"
+    KeyStatusMismatched = "mismatched"
+    KeyStatusWrongPath = "wrong-path"
+    KeyStatusPathUnverified = "path-unverified"
+)
+
+
+// Validator key audit result
+type ValidatorKeyAudit struct {
+    Index uint
+    Pubkey rptypes.ValidatorPubkey
+    DerivationPath string
+    Keystores map[string]KeystoreKeyAudit
+}
+
+
+// Validator key audit result for a single keystore
+type KeystoreKeyAudit struct {
+    Status string
+    Detail string
+}
+
+
+// Audit validator keys by index against each keystore
+// Checks that each key is present, decrypts to the derived key, and was stored with the correct derivation path
+// Keys in keystores which cannot be listed are reported as undecryptable, and keys in keystores which do not store paths as path-unverified
+func (w *Wallet) AuditValidatorKeys(indices []uint) ([]ValidatorKeyAudit, error) {
+
+    // Check wallet is initialized
+    if !w.IsInitialized() {
+        return nil, errors.New("Wallet is not initialized")
+    }
+
+    // Get stored pubkeys by keystore
+    stored := make(map[string]map[rptypes.ValidatorPubkey]bool, len(w.keystores))
+    listErrors := make(map[string]error, len(w.keystores))
+    for name, ks := range w.keystores {
+        pubkeys, err := ks.ListValidatorKeys()
+        if err != nil {
+            listErrors[name] = fmt.Errorf("Could not list %s validator keys: %w", name, err)
+            continue
+        }
+        stored[name] = make(map[rptypes.ValidatorPubkey]bool, len(pubkeys))
+        for _, pubkey := range pubkeys {
+            stored[name][pubkey] = true
+        }
+    }
+
+    // Audit validator keys
+    audits := make([]ValidatorKeyAudit, len(indices))
+    for ai, index := range indices {
+
+        // Get validator key
+        key, path, err := w.getValidatorPrivateKey(index)
+        if err != nil {
+            return nil, err
+        }
+        pubkey := rptypes.BytesToValidatorPubkey(key.PublicKey().Marshal())
+
+        // Check keystores
+        keystoreAudits := make(map[string]KeystoreKeyAudit, len(w.keystores))
+        for name, ks := range w.keystores {
+
+            // Check keystore could be listed
+            if listErr, ok := listErrors[name]; ok {
+                keystoreAudits[name] = KeystoreKeyAudit{Status: KeyStatusUndecryptable, Detail: listErr.Error()}
+                continue
+            }
+
+            // Check key is present; remote keys cannot be loaded, so only their presence is checked
+            if !stored[name][pubkey] {
+                keystoreAudits[name] = KeystoreKeyAudit{Status: KeyStatusMissing}
+                continue
+            }
+            if _, ok := ks.(keystore.RemoteKeystore); ok {
+                keystoreAudits[name] = KeystoreKeyAudit{Status: KeyStatusOK}
+                continue
+            }
+
+            // Check key decrypts to the derived key
+            storedKey, err := ks.LoadValidatorKey(pubkey)
+            if err != nil {
+                keystoreAudits[name] = KeystoreKeyAudit{Status: KeyStatusUndecryptable, Detail: err.Error()}
+                continue
+            }
+            if !bytes.Equal(key.Marshal(), storedKey.Marshal()) {
+                keystoreAudits[name] = KeystoreKeyAudit{Status: KeyStatusMismatched}
+                continue
+            }
+
+            // Check key derivation path
+            pks, ok := ks.(keystore.PathKeystore)
+            if !ok {
+                keystoreAudits[name] = KeystoreKeyAudit{Status: KeyStatusPathUnverified, Detail: "keystore does not store derivation paths"}
+                continue
+            }
+            storedPath, err := pks.GetValidatorKeyPath(pubkey)
+            if err != nil {
+                keystoreAudits[name] = KeystoreKeyAudit{Status: KeyStatusUndecryptable, Detail: err.Error()}
+                continue
+            }
+            if storedPath != path {
+                keystoreAudits[name] = KeystoreKeyAudit{Status: KeyStatusWrongPath, Detail: fmt.Sprintf("stored with path %s", storedPath)}
+                continue
+            }
+
+            // Key is valid
+            keystoreAudits[name] = KeystoreKeyAudit{Status: KeyStatusOK}
+
+        }
+
+        // Add audit result
+        audits[ai] = ValidatorKeyAudit{
+            Index: index,
+            Pubkey: pubkey,
+            DerivationPath: path,
+            Keystores: keystoreAudits,
+        }
+
+    }
+
+    // Return
+    return audits, nil
+
+}
+
+
+// Store a validator key by index in the named keystores only
+func (w *Wallet) RepairValidatorKey(index uint, keystoreNames []string) error {
+
+    // Check wallet is initialized
+    if !w.IsInitialized() {
+        return errors.New("Wallet is not initialized")
+    }
+
+    // Get validator key
+    key, path, err := w.getValidatorPrivateKey(index)
+    if err != nil {
+        return err
+    }
+
+    // Update keystores
+    for _, name := range keystoreNames {
+        ks, ok := w.keystores[name]
+        if !ok {
+            return fmt.Errorf("Unknown keystore %s", name)
+        }
+        if err := ks.StoreValidatorKey(key, path); err != nil {
+            return fmt.Errorf("Could not store %s validator key: %w", name, err)
+        }
+    }
+
+    // Return
+    return nil
+
+}
+
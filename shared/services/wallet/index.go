@@ -0,0 +1,137 @@
+package wallet
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+
+    rptypes "github.com/rocket-pool/rocketpool-go/types"
+)
+
+
+// Rebuild the validator key index from all derived validator keys
+// The rebuilt index is persisted on the next wallet save
+func (w *Wallet) RebuildValidatorKeyIndex() error {
+
+    // Check wallet is initialized
+    if !w.IsInitialized() {
+        return errors.New("Wallet is not initialized")
+    }
+
+    // Derive validator keys
+    keys, err := w.deriveValidatorKeys(0, w.ws.NextAccount)
+    if err != nil {
+        return err
+    }
+
+    // Rebuild index
+    w.validatorKeyIndices = make(map[string]uint, len(keys))
+    for ki, key := range keys {
+        w.validatorKeyIndices[rptypes.BytesToValidatorPubkey(key.PublicKey().Marshal()).Hex()] = uint(ki)
+    }
+    w.validatorKeyIndicesLoaded = true
+    w.validatorKeyIndicesUpdated = true
+
+    // Return
+    return nil
+
+}
+
+
+// Get a validator key's index from the validator key index
+func (w *Wallet) getValidatorKeyIndex(pubkey rptypes.ValidatorPubkey) (uint, bool, error) {
+    if err := w.loadValidatorKeyIndices(); err != nil {
+        return 0, false, err
+    }
+    index, ok := w.validatorKeyIndices[pubkey.Hex()]
+    return index, ok, nil
+}
+
+
+// Record a validator key's index in the validator key index
+// The index is persisted on the next wallet save
+func (w *Wallet) setValidatorKeyIndex(pubkey rptypes.ValidatorPubkey, index uint) error {
+    if err := w.loadValidatorKeyIndices(); err != nil {
+        return err
+    }
+    if current, ok := w.validatorKeyIndices[pubkey.Hex()]; ok && current == index {
+        return nil
+    }
+    w.validatorKeyIndices[pubkey.Hex()] = index
+    w.validatorKeyIndicesUpdated = true
+    return nil
+}
+
+
+// Decrypt the persisted validator key index
+// The index is only decrypted when first needed, as decryption is slow
+// Wallets created before the index was added have it built from all derived validator keys instead
+func (w *Wallet) loadValidatorKeyIndices() error {
+
+    // Cancel if already loaded
+    if w.validatorKeyIndicesLoaded {
+        return nil
+    }
+
+    // Build index if not persisted
+    if w.ws.KeyIndex == nil {
+        if w.ws.NextAccount > 0 {
+            return w.RebuildValidatorKeyIndex()
+        }
+        w.validatorKeyIndices = map[string]uint{}
+        w.validatorKeyIndicesLoaded = true
+        return nil
+    }
+
+    // Get wallet password
+    password, err := w.pm.GetPassword()
+    if err != nil {
+        return fmt.Errorf("Could not get wallet password: %w", err)
+    }
+
+    // Decrypt & decode index
+    indicesBytes, err := w.encryptor.Decrypt(w.ws.KeyIndex, password)
+    if err != nil {
+        return fmt.Errorf("Could not decrypt validator key index: %w", err)
+    }
+    indices := map[string]uint{}
+    if err := json.Unmarshal(indicesBytes, &indices); err != nil {
+        return fmt.Errorf("Could not decode validator key index: %w", err)
+    }
+
+    // Set index & return
+    w.validatorKeyIndices = indices
+    w.validatorKeyIndicesLoaded = true
+    return nil
+
+}
+
+
+// Encrypt the validator key index into the wallet store
+func (w *Wallet) encryptValidatorKeyIndices() error {
+
+    // Encode index
+    indicesBytes, err := json.Marshal(w.validatorKeyIndices)
+    if err != nil {
+        return fmt.Errorf("Could not encode validator key index: %w", err)
+    }
+
+    // Get wallet password
+    password, err := w.pm.GetPassword()
+    if err != nil {
+        return fmt.Errorf("Could not get wallet password: %w", err)
+    }
+
+    // Encrypt index
+    encryptedIndices, err := w.encryptor.Encrypt(indicesBytes, password)
+    if err != nil {
+        return fmt.Errorf("Could not encrypt validator key index: %w", err)
+    }
+
+    // Update wallet store & return
+    w.ws.KeyIndex = encryptedIndices
+    w.validatorKeyIndicesUpdated = false
+    return nil
+
+}
+
@@ -1,6 +1,7 @@
 package keystore
 
 import (
+    rptypes "github.com/rocket-pool/rocketpool-go/types"
     eth2types "github.com/wealdtech/go-eth2-types/v2"
 )
 
@@ -8,5 +9,23 @@ import (
 // Validator keystore interface
 type Keystore interface {
     StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPath string) error
+    LoadValidatorKey(pubkey rptypes.ValidatorPubkey) (*eth2types.BLSPrivateKey, error)
+    DeleteValidatorKey(pubkey rptypes.ValidatorPubkey) error
+    ListValidatorKeys() ([]rptypes.ValidatorPubkey, error)
+}
+
+
+// Remote validator keystore interface
+// Keys held by a remote signer cannot be loaded back, so their presence is checked instead
+type RemoteKeystore interface {
+    Keystore
+    HasValidatorKey(pubkey rptypes.ValidatorPubkey) (bool, error)
+}
+
+
+// Validator keystore interface for keystores which record key derivation paths
+type PathKeystore interface {
+    Keystore
+    GetValidatorKeyPath(pubkey rptypes.ValidatorPubkey) (string, error)
 }
 
@@ -10,10 +10,12 @@ import (
     "path/filepath"
 
     "github.com/google/uuid"
+    rptypes "github.com/rocket-pool/rocketpool-go/types"
     eth2types "github.com/wealdtech/go-eth2-types/v2"
     eth2ks "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
 
     "github.com/rocket-pool/smartnode/shared/services/passwords"
+    "github.com/rocket-pool/smartnode/shared/utils/files"
 )
 
 
@@ -89,6 +91,117 @@ func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPa
     ks.as.PrivateKeys = append(ks.as.PrivateKeys, key.Marshal())
     ks.as.PublicKeys = append(ks.as.PublicKeys, key.PublicKey().Marshal())
 
+    // Save account store
+    return ks.saveAccountStore()
+
+}
+
+
+// Load a stored validator key by public key
+func (ks *Keystore) LoadValidatorKey(pubkey rptypes.ValidatorPubkey) (*eth2types.BLSPrivateKey, error) {
+
+    // Read account store from disk
+    as, err := ks.readAccountStore()
+    if err != nil {
+        return nil, err
+    }
+    if as == nil {
+        return nil, errors.New("Validator account store does not exist")
+    }
+
+    // Find validator key
+    for ki := 0; ki < len(as.PublicKeys); ki++ {
+        if bytes.Equal(pubkey.Bytes(), as.PublicKeys[ki]) {
+            key, err := eth2types.BLSPrivateKeyFromBytes(as.PrivateKeys[ki])
+            if err != nil {
+                return nil, fmt.Errorf("Could not decode validator private key: %w", err)
+            }
+            return key, nil
+        }
+    }
+
+    // Return
+    return nil, fmt.Errorf("Validator key %s not found in account store", pubkey.Hex())
+
+}
+
+
+// Delete a stored validator key by public key
+// Deleting a key which is not stored has no effect
+func (ks *Keystore) DeleteValidatorKey(pubkey rptypes.ValidatorPubkey) error {
+
+    // Initialize the account store
+    if err := ks.initialize(); err != nil {
+        return err
+    }
+
+    // Remove validator key from account store
+    privateKeys := [][]byte{}
+    publicKeys := [][]byte{}
+    for ki := 0; ki < len(ks.as.PublicKeys); ki++ {
+        if bytes.Equal(pubkey.Bytes(), ks.as.PublicKeys[ki]) { continue }
+        privateKeys = append(privateKeys, ks.as.PrivateKeys[ki])
+        publicKeys = append(publicKeys, ks.as.PublicKeys[ki])
+    }
+    if len(publicKeys) == len(ks.as.PublicKeys) {
+        return nil
+    }
+    ks.as.PrivateKeys = privateKeys
+    ks.as.PublicKeys = publicKeys
+
+    // Save account store
+    return ks.saveAccountStore()
+
+}
+
+
+// Get the public keys of all stored validator keys
+func (ks *Keystore) ListValidatorKeys() ([]rptypes.ValidatorPubkey, error) {
+
+    // Read account store from disk
+    as, err := ks.readAccountStore()
+    if err != nil {
+        return []rptypes.ValidatorPubkey{}, err
+    }
+    if as == nil {
+        return []rptypes.ValidatorPubkey{}, nil
+    }
+
+    // Get pubkeys
+    pubkeys := make([]rptypes.ValidatorPubkey, len(as.PublicKeys))
+    for ki, pubkey := range as.PublicKeys {
+        pubkeys[ki] = rptypes.BytesToValidatorPubkey(pubkey)
+    }
+
+    // Return
+    return pubkeys, nil
+
+}
+
+
+// Initialize the account store
+// The account store is re-read from disk on each call, as it may have been modified by another process
+func (ks *Keystore) initialize() error {
+
+    // Read account store; initialize empty account store if it doesn't exist
+    as, err := ks.readAccountStore()
+    if err != nil {
+        return err
+    }
+    if as == nil {
+        as = &accountStore{}
+    }
+
+    // Set account store & return
+    ks.as = as
+    return nil
+
+}
+
+
+// Save the account store to disk
+func (ks *Keystore) saveAccountStore() error {
+
     // Encode account store
     asBytes, err := json.Marshal(ks.as)
     if err != nil {
@@ -131,7 +244,7 @@ func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPa
     }
 
     // Write keystore to disk
-    if err := ioutil.WriteFile(keystoreFilePath, ksBytes, FileMode); err != nil {
+    if err := files.WriteFileAtomic(keystoreFilePath, ksBytes, FileMode); err != nil {
         return fmt.Errorf("Could not write keystore to disk: %w", err)
     }
 
@@ -149,7 +262,7 @@ func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPa
     }
 
     // Write wallet config to disk
-    if err := ioutil.WriteFile(configFilePath, configBytes, FileMode); err != nil {
+    if err := files.WriteFileAtomic(configFilePath, configBytes, FileMode); err != nil {
         return fmt.Errorf("Could not write wallet config to disk: %w", err)
     }
 
@@ -159,51 +272,48 @@ func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPa
 }
 
 
-// Initialize the account store
-func (ks *Keystore) initialize() error {
+// Read the account store from disk
+// Returns nil if the keystore file doesn't exist
+func (ks *Keystore) readAccountStore() (*accountStore, error) {
 
-    // Cancel if already initialized
-    if ks.as != nil {
-        return nil
-    }
-
-    // Read keystore file; initialize empty account store if it doesn't exist
+    // Read keystore file
     ksBytes, err := ioutil.ReadFile(filepath.Join(ks.keystorePath, KeystoreDir, WalletDir, AccountsDir, KeystoreFileName))
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
     if err != nil {
-        ks.as = &accountStore{}
-        return nil
+        return nil, fmt.Errorf("Could not read validator keystore: %w", err)
     }
 
     // Decode keystore
     keystore := &validatorKeystore{}
     if err = json.Unmarshal(ksBytes, keystore); err != nil {
-        return fmt.Errorf("Could not decode validator keystore: %w", err)
+        return nil, fmt.Errorf("Could not decode validator keystore: %w", err)
     }
 
     // Get wallet password
     password, err := ks.pm.GetPassword()
     if err != nil {
-        return fmt.Errorf("Could not get wallet password: %w", err)
+        return nil, fmt.Errorf("Could not get wallet password: %w", err)
     }
 
     // Decrypt account store
     asBytes, err := ks.encryptor.Decrypt(keystore.Crypto, password)
     if err != nil {
-        return fmt.Errorf("Could not decrypt validator account store: %w", err)
+        return nil, fmt.Errorf("Could not decrypt validator account store: %w", err)
     }
 
     // Decode account store
     as := &accountStore{}
     if err = json.Unmarshal(asBytes, as); err != nil {
-        return fmt.Errorf("Could not decode validator account store: %w", err)
+        return nil, fmt.Errorf("Could not decode validator account store: %w", err)
     }
     if len(as.PrivateKeys) != len(as.PublicKeys) {
-        return errors.New("Validator account store private and public key counts do not match")
+        return nil, errors.New("Validator account store private and public key counts do not match")
     }
 
-    // Set account store & return
-    ks.as = as
-    return nil
+    // Return
+    return as, nil
 
 }
 
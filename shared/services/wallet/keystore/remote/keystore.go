@@ -0,0 +1,105 @@
+package remote
+
+import (
+    "errors"
+    "fmt"
+
+    rptypes "github.com/rocket-pool/rocketpool-go/types"
+    eth2types "github.com/wealdtech/go-eth2-types/v2"
+
+    "github.com/rocket-pool/smartnode/shared/services/keymanager"
+    "github.com/rocket-pool/smartnode/shared/services/passwords"
+)
+
+
+// Remote signer keystore
+// Imports validator keys into a Web3Signer-compatible remote signer via its key manager API
+type Keystore struct {
+    km *keymanager.Client
+    pm *passwords.PasswordManager
+}
+
+
+// Create new remote signer keystore
+func NewKeystore(signerUrl string, tokenPath string, passwordManager *passwords.PasswordManager) *Keystore {
+    return &Keystore{
+        km: keymanager.NewClient(signerUrl, tokenPath),
+        pm: passwordManager,
+    }
+}
+
+
+// Store a validator key
+func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPath string) error {
+
+    // Get wallet password
+    password, err := ks.pm.GetPassword()
+    if err != nil {
+        return fmt.Errorf("Could not get wallet password: %w", err)
+    }
+
+    // Import key
+    statuses, err := ks.km.ImportKeys([]*eth2types.BLSPrivateKey{key}, password)
+    if err != nil {
+        return err
+    }
+
+    // Check import status; keys already held by the signer are reported as duplicates
+    if status := statuses[0]; status.Status != keymanager.StatusImported && status.Status != keymanager.StatusDuplicate {
+        return fmt.Errorf("Validator key was not imported by the remote signer: %s %s", status.Status, status.Message)
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Load a stored validator key by public key
+// Keys cannot be exported from the remote signer
+func (ks *Keystore) LoadValidatorKey(pubkey rptypes.ValidatorPubkey) (*eth2types.BLSPrivateKey, error) {
+    return nil, errors.New("Validator keys cannot be loaded from a remote signer")
+}
+
+
+// Delete a validator key from the remote signer
+// Deleting a key which is not held by the signer has no effect
+func (ks *Keystore) DeleteValidatorKey(pubkey rptypes.ValidatorPubkey) error {
+
+    // Delete key
+    statuses, err := ks.km.DeleteKeys([]rptypes.ValidatorPubkey{pubkey})
+    if err != nil {
+        return err
+    }
+
+    // Check deletion status
+    if status := statuses[0]; status.Status != keymanager.StatusDeleted && status.Status != keymanager.StatusNotActive && status.Status != keymanager.StatusNotFound {
+        return fmt.Errorf("Validator key was not deleted by the remote signer: %s %s", status.Status, status.Message)
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Get the public keys of all validator keys held by the remote signer
+func (ks *Keystore) ListValidatorKeys() ([]rptypes.ValidatorPubkey, error) {
+    return ks.km.ListKeys()
+}
+
+
+// Check whether the remote signer holds a validator key
+func (ks *Keystore) HasValidatorKey(pubkey rptypes.ValidatorPubkey) (bool, error) {
+    pubkeys, err := ks.km.ListKeys()
+    if err != nil {
+        return false, err
+    }
+    for _, signerPubkey := range pubkeys {
+        if signerPubkey == pubkey {
+            return true, nil
+        }
+    }
+    return false, nil
+}
+
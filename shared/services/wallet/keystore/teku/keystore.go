@@ -6,6 +6,7 @@ import (
     "io/ioutil"
     "os"
     "path/filepath"
+    "strings"
 
     "github.com/google/uuid"
     rptypes "github.com/rocket-pool/rocketpool-go/types"
@@ -13,6 +14,7 @@ import (
     eth2ks "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
 
     "github.com/rocket-pool/smartnode/shared/services/passwords"
+    "github.com/rocket-pool/smartnode/shared/utils/files"
     hexutil "github.com/rocket-pool/smartnode/shared/utils/hex"
 )
 
@@ -92,7 +94,7 @@ func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPa
     }
 
     // Write secret to disk
-    if err := ioutil.WriteFile(secretFilePath, []byte(password), FileMode); err != nil {
+    if err := files.WriteFileAtomic(secretFilePath, []byte(password), FileMode); err != nil {
         return fmt.Errorf("Could not write validator secret to disk: %w", err)
     }
 
@@ -105,7 +107,7 @@ func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPa
     }
 
     // Write key store to disk
-    if err := ioutil.WriteFile(keyFilePath, keyStoreBytes, FileMode); err != nil {
+    if err := files.WriteFileAtomic(keyFilePath, keyStoreBytes, FileMode); err != nil {
         return fmt.Errorf("Could not write validator key to disk: %w", err)
     }
 
@@ -113,3 +115,119 @@ func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPa
     return nil
 
 }
+
+// Load a stored validator key by public key
+func (ks *Keystore) LoadValidatorKey(pubkey rptypes.ValidatorPubkey) (*eth2types.BLSPrivateKey, error) {
+
+    // Get secret & key file paths
+    secretFilePath := filepath.Join(ks.keystorePath, KeystoreDir, SecretsDir, hexutil.AddPrefix(pubkey.Hex())+".txt")
+    keyFilePath := filepath.Join(ks.keystorePath, KeystoreDir, ValidatorsDir, hexutil.AddPrefix(pubkey.Hex())+".json")
+
+    // Read secret & key store from disk
+    password, err := ioutil.ReadFile(secretFilePath)
+    if err != nil {
+        return nil, fmt.Errorf("Could not read validator secret from disk: %w", err)
+    }
+    keyStoreBytes, err := ioutil.ReadFile(keyFilePath)
+    if err != nil {
+        return nil, fmt.Errorf("Could not read validator key from disk: %w", err)
+    }
+
+    // Decode key store
+    var keyStore validatorKey
+    if err := json.Unmarshal(keyStoreBytes, &keyStore); err != nil {
+        return nil, fmt.Errorf("Could not decode validator key: %w", err)
+    }
+    if keyStore.Pubkey != pubkey {
+        return nil, fmt.Errorf("Validator key file contains key %s", keyStore.Pubkey.Hex())
+    }
+
+    // Decrypt key
+    keyBytes, err := ks.encryptor.Decrypt(keyStore.Crypto, string(password))
+    if err != nil {
+        return nil, fmt.Errorf("Could not decrypt validator key: %w", err)
+    }
+    key, err := eth2types.BLSPrivateKeyFromBytes(keyBytes)
+    if err != nil {
+        return nil, fmt.Errorf("Could not decode validator private key: %w", err)
+    }
+
+    // Return
+    return key, nil
+
+}
+
+// Get the derivation path recorded for a stored validator key
+func (ks *Keystore) GetValidatorKeyPath(pubkey rptypes.ValidatorPubkey) (string, error) {
+
+    // Read key store from disk
+    keyStoreBytes, err := ioutil.ReadFile(filepath.Join(ks.keystorePath, KeystoreDir, ValidatorsDir, hexutil.AddPrefix(pubkey.Hex())+".json"))
+    if err != nil {
+        return "", fmt.Errorf("Could not read validator key from disk: %w", err)
+    }
+
+    // Decode key store
+    var keyStore validatorKey
+    if err := json.Unmarshal(keyStoreBytes, &keyStore); err != nil {
+        return "", fmt.Errorf("Could not decode validator key: %w", err)
+    }
+
+    // Return
+    return keyStore.Path, nil
+
+}
+
+// Delete a stored validator key by public key
+// Deleting a key which is not stored has no effect
+func (ks *Keystore) DeleteValidatorKey(pubkey rptypes.ValidatorPubkey) error {
+
+    // Get secret & key file paths
+    secretFilePath := filepath.Join(ks.keystorePath, KeystoreDir, SecretsDir, hexutil.AddPrefix(pubkey.Hex())+".txt")
+    keyFilePath := filepath.Join(ks.keystorePath, KeystoreDir, ValidatorsDir, hexutil.AddPrefix(pubkey.Hex())+".json")
+
+    // Delete key store & secret from disk
+    if err := os.Remove(keyFilePath); err != nil && !os.IsNotExist(err) {
+        return fmt.Errorf("Could not delete validator key from disk: %w", err)
+    }
+    if err := os.Remove(secretFilePath); err != nil && !os.IsNotExist(err) {
+        return fmt.Errorf("Could not delete validator secret from disk: %w", err)
+    }
+
+    // Return
+    return nil
+
+}
+
+// Get the public keys of all stored validator keys
+func (ks *Keystore) ListValidatorKeys() ([]rptypes.ValidatorPubkey, error) {
+
+    // Read validator key files
+    entries, err := ioutil.ReadDir(filepath.Join(ks.keystorePath, KeystoreDir, ValidatorsDir))
+    if os.IsNotExist(err) {
+        return []rptypes.ValidatorPubkey{}, nil
+    }
+    if err != nil {
+        return []rptypes.ValidatorPubkey{}, fmt.Errorf("Could not read validator keys folder: %w", err)
+    }
+
+    // Get pubkeys from file names
+    pubkeys := []rptypes.ValidatorPubkey{}
+    for _, entry := range entries {
+        if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+            continue
+        }
+        pubkeyHex := hexutil.RemovePrefix(strings.TrimSuffix(entry.Name(), ".json"))
+        if len(pubkeyHex) != rptypes.ValidatorPubkeyLength*2 {
+            continue
+        }
+        pubkey, err := rptypes.HexToValidatorPubkey(pubkeyHex)
+        if err != nil {
+            continue
+        }
+        pubkeys = append(pubkeys, pubkey)
+    }
+
+    // Return
+    return pubkeys, nil
+
+}
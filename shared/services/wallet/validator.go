@@ -9,13 +9,17 @@ import (
     rptypes "github.com/rocket-pool/rocketpool-go/types"
     eth2types "github.com/wealdtech/go-eth2-types/v2"
     eth2util "github.com/wealdtech/go-eth2-util"
+    "golang.org/x/sync/errgroup"
+
+    "github.com/rocket-pool/smartnode/shared/services/wallet/keystore"
 )
 
 
 // Config
 const (
     ValidatorKeyPath = "m/12381/3600/%d/0/0"
-    MaxValidatorKeyRecoverAttempts = 100
+    DefaultValidatorKeyRecoverWindow = 100
+    ValidatorKeyDeriveBatchSize = 50
 )
 
 
@@ -56,40 +60,24 @@ func (w *Wallet) GetValidatorKeyByPubkey(pubkey rptypes.ValidatorPubkey) (*eth2t
         return nil, errors.New("Wallet is not initialized")
     }
 
-    // Get pubkey hex string
-    pubkeyHex := pubkey.Hex()
-
-    // Check for cached validator key index
-    if index, ok := w.validatorKeyIndices[pubkeyHex]; ok {
-        if key, _, err := w.getValidatorPrivateKey(index); err != nil {
-            return nil, err
-        } else if bytes.Equal(pubkey.Bytes(), key.PublicKey().Marshal()) {
-            return key, nil
-        }
+    // Find validator key index
+    indices, err := w.findValidatorKeyIndices([]rptypes.ValidatorPubkey{pubkey}, 0)
+    if err != nil {
+        return nil, err
     }
-
-    // Find matching validator key
-    var index uint
-    var validatorKey *eth2types.BLSPrivateKey
-    for index = 0; index < w.ws.NextAccount; index++ {
-        if key, _, err := w.getValidatorPrivateKey(index); err != nil {
-            return nil, err
-        } else if bytes.Equal(pubkey.Bytes(), key.PublicKey().Marshal()) {
-            validatorKey = key
-            break
-        }
+    index, ok := indices[pubkey]
+    if !ok {
+        return nil, fmt.Errorf("Validator %s key not found", pubkey.Hex())
     }
 
-    // Check validator key
-    if validatorKey == nil {
-        return nil, fmt.Errorf("Validator %s key not found", pubkeyHex)
+    // Record validator key index; it is not saved here, as the wallet may have been modified by another process since it was loaded
+    if err := w.setValidatorKeyIndex(pubkey, index); err != nil {
+        return nil, err
     }
 
-    // Cache validator key index
-    w.validatorKeyIndices[pubkeyHex] = index
-
-    // Return
-    return validatorKey, nil
+    // Return validator key
+    key, _, err := w.getValidatorPrivateKey(index)
+    return key, err
 
 }
 
@@ -102,19 +90,33 @@ func (w *Wallet) CreateValidatorKey() (*eth2types.BLSPrivateKey, error) {
         return nil, errors.New("Wallet is not initialized")
     }
 
-    // Get & increment account index
-    index := w.ws.NextAccount
-    w.ws.NextAccount++
-
     // Get validator key
+    index := w.ws.NextAccount
     key, path, err := w.getValidatorPrivateKey(index)
     if err != nil {
         return nil, err
     }
 
-    // Update keystores
+    // Record validator key index
+    if err := w.setValidatorKeyIndex(rptypes.BytesToValidatorPubkey(key.PublicKey().Marshal()), index); err != nil {
+        return nil, err
+    }
+
+    // Increment account index & record key as pending, and save wallet before the key is used so the index is never reused
+    w.ws.NextAccount++
+    w.ws.PendingAccounts = append(w.ws.PendingAccounts, index)
+    if err := w.Save(); err != nil {
+        w.ws.NextAccount--
+        w.ws.PendingAccounts = w.ws.PendingAccounts[:len(w.ws.PendingAccounts) - 1]
+        return nil, err
+    }
+
+    // Update keystores; the key is no longer pending if it could not be stored, as it will not be used
     for name, ks := range w.keystores {
         if err := ks.StoreValidatorKey(key, path); err != nil {
+            if clearErr := w.ClearPendingValidatorKey(key); clearErr != nil {
+                return nil, fmt.Errorf("Could not store %s validator key: %w; could not clear pending validator key: %s", name, err, clearErr.Error())
+            }
             return nil, fmt.Errorf("Could not store %s validator key: %w", name, err)
         }
     }
@@ -125,43 +127,179 @@ func (w *Wallet) CreateValidatorKey() (*eth2types.BLSPrivateKey, error) {
 }
 
 
-// Recover a validator key by public key
+// Get validator keys which are pending confirmation of their use
+func (w *Wallet) GetPendingValidatorKeys() ([]*eth2types.BLSPrivateKey, error) {
+
+    // Check wallet is initialized
+    if !w.IsInitialized() {
+        return nil, errors.New("Wallet is not initialized")
+    }
+
+    // Get validator keys
+    keys := make([]*eth2types.BLSPrivateKey, len(w.ws.PendingAccounts))
+    for ki, index := range w.ws.PendingAccounts {
+        key, _, err := w.getValidatorPrivateKey(index)
+        if err != nil {
+            return nil, err
+        }
+        keys[ki] = key
+    }
+
+    // Return
+    return keys, nil
+
+}
+
+
+// Clear a pending validator key once its use has been resolved and save the wallet
+func (w *Wallet) ClearPendingValidatorKey(key *eth2types.BLSPrivateKey) error {
+
+    // Check wallet is initialized
+    if !w.IsInitialized() {
+        return errors.New("Wallet is not initialized")
+    }
+
+    // Remove matching pending account indices
+    pendingAccounts := []uint{}
+    for _, index := range w.ws.PendingAccounts {
+        pendingKey, _, err := w.getValidatorPrivateKey(index)
+        if err != nil {
+            return err
+        }
+        if !bytes.Equal(key.Marshal(), pendingKey.Marshal()) {
+            pendingAccounts = append(pendingAccounts, index)
+        }
+    }
+    w.ws.PendingAccounts = pendingAccounts
+
+    // Save wallet
+    return w.Save()
+
+}
+
+
+// Recover a validator key by public key, searching the default window beyond the next account index
 func (w *Wallet) RecoverValidatorKey(pubkey rptypes.ValidatorPubkey) error {
+    return w.RecoverValidatorKeys([]rptypes.ValidatorPubkey{pubkey}, DefaultValidatorKeyRecoverWindow)
+}
+
+
+// Recover validator keys by public key
+// Keys are searched for up to searchWindow indices beyond the next account index and the highest recovered key
+func (w *Wallet) RecoverValidatorKeys(pubkeys []rptypes.ValidatorPubkey, searchWindow uint) error {
 
     // Check wallet is initialized
     if !w.IsInitialized() {
         return errors.New("Wallet is not initialized")
     }
 
-    // Find matching validator key
-    var index uint
-    var validatorKey *eth2types.BLSPrivateKey
-    var derivationPath string
-    for index = 0; index < w.ws.NextAccount + MaxValidatorKeyRecoverAttempts; index++ {
-        if key, path, err := w.getValidatorPrivateKey(index); err != nil {
+    // Find validator key indices
+    indices, err := w.findValidatorKeyIndices(pubkeys, searchWindow)
+    if err != nil {
+        return err
+    }
+    for _, pubkey := range pubkeys {
+        if _, ok := indices[pubkey]; !ok {
+            return fmt.Errorf("Validator %s key not found", pubkey.Hex())
+        }
+    }
+
+    // Recover validator keys
+    for _, pubkey := range pubkeys {
+        index := indices[pubkey]
+
+        // Get validator key
+        validatorKey, derivationPath, err := w.getValidatorPrivateKey(index)
+        if err != nil {
             return err
-        } else if bytes.Equal(pubkey.Bytes(), key.PublicKey().Marshal()) {
-            validatorKey = key
-            derivationPath = path
-            break
+        }
+
+        // Update account index & validator key index
+        nextIndex := index + 1
+        if nextIndex > w.ws.NextAccount {
+            w.ws.NextAccount = nextIndex
+        }
+        if err := w.setValidatorKeyIndex(pubkey, index); err != nil {
+            return err
+        }
+
+        // Update keystores
+        for name, ks := range w.keystores {
+            if err := ks.StoreValidatorKey(validatorKey, derivationPath); err != nil {
+                return fmt.Errorf("Could not store %s validator key: %w", name, err)
+            }
+        }
+
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Get the public keys of validator keys stored in any keystore
+func (w *Wallet) GetStoredValidatorKeys() ([]rptypes.ValidatorPubkey, error) {
+
+    // Get stored pubkeys across keystores
+    pubkeys := []rptypes.ValidatorPubkey{}
+    seen := make(map[rptypes.ValidatorPubkey]bool)
+    for name, ks := range w.keystores {
+        keystorePubkeys, err := ks.ListValidatorKeys()
+        if err != nil {
+            return nil, fmt.Errorf("Could not list %s validator keys: %w", name, err)
+        }
+        for _, pubkey := range keystorePubkeys {
+            if seen[pubkey] { continue }
+            seen[pubkey] = true
+            pubkeys = append(pubkeys, pubkey)
         }
     }
 
-    // Check validator key
-    if validatorKey == nil {
-        return fmt.Errorf("Validator %s key not found", pubkey.Hex())
+    // Return
+    return pubkeys, nil
+
+}
+
+
+// Delete a validator key from all keystores
+func (w *Wallet) DeleteValidatorKey(pubkey rptypes.ValidatorPubkey) error {
+    for name, ks := range w.keystores {
+        if err := ks.DeleteValidatorKey(pubkey); err != nil {
+            return fmt.Errorf("Could not delete %s validator key: %w", name, err)
+        }
     }
+    return nil
+}
 
-    // Update account index
-    nextIndex := index + 1
-    if nextIndex > w.ws.NextAccount {
-        w.ws.NextAccount = nextIndex
+
+// Verify that a validator key was stored successfully in all keystores
+func (w *Wallet) VerifyValidatorKey(key *eth2types.BLSPrivateKey) error {
+
+    // Check wallet is initialized
+    if !w.IsInitialized() {
+        return errors.New("Wallet is not initialized")
     }
 
-    // Update keystores
+    // Get validator pubkey
+    pubkey := rptypes.BytesToValidatorPubkey(key.PublicKey().Marshal())
+
+    // Load & check stored keys; check remote keys are held by their signer
     for name, ks := range w.keystores {
-        if err := ks.StoreValidatorKey(validatorKey, derivationPath); err != nil {
-            return fmt.Errorf("Could not store %s validator key: %w", name, err)
+        if rks, ok := ks.(keystore.RemoteKeystore); ok {
+            if hasKey, err := rks.HasValidatorKey(pubkey); err != nil {
+                return fmt.Errorf("Could not check %s validator key: %w", name, err)
+            } else if !hasKey {
+                return fmt.Errorf("Validator key %s is not held by the %s signer", pubkey.Hex(), name)
+            }
+            continue
+        }
+        storedKey, err := ks.LoadValidatorKey(pubkey)
+        if err != nil {
+            return fmt.Errorf("Could not load %s validator key: %w", name, err)
+        }
+        if !bytes.Equal(key.Marshal(), storedKey.Marshal()) {
+            return fmt.Errorf("Stored %s validator key %s does not match", name, pubkey.Hex())
         }
     }
 
@@ -171,24 +309,124 @@ func (w *Wallet) RecoverValidatorKey(pubkey rptypes.ValidatorPubkey) error {
 }
 
 
+// Find validator key indices by public key
+// Indexed keys are checked first; remaining keys are searched for by deriving keys in parallel batches from index 0
+// The search extends up to searchWindow indices beyond the next account index and the highest key found
+func (w *Wallet) findValidatorKeyIndices(pubkeys []rptypes.ValidatorPubkey, searchWindow uint) (map[rptypes.ValidatorPubkey]uint, error) {
+
+    // Check validator key index
+    indices := make(map[rptypes.ValidatorPubkey]uint, len(pubkeys))
+    remaining := make(map[rptypes.ValidatorPubkey]bool)
+    for _, pubkey := range pubkeys {
+        index, ok, err := w.getValidatorKeyIndex(pubkey)
+        if err != nil {
+            return nil, err
+        }
+        if ok {
+            key, _, err := w.getValidatorPrivateKey(index)
+            if err != nil {
+                return nil, err
+            }
+            if bytes.Equal(pubkey.Bytes(), key.PublicKey().Marshal()) {
+                indices[pubkey] = index
+                continue
+            }
+        }
+        remaining[pubkey] = true
+    }
+
+    // Search derived keys
+    searchEnd := w.ws.NextAccount + searchWindow
+    for batchStart := uint(0); batchStart < searchEnd && len(remaining) > 0; batchStart += ValidatorKeyDeriveBatchSize {
+
+        // Derive batch of keys
+        batchEnd := batchStart + ValidatorKeyDeriveBatchSize
+        if batchEnd > searchEnd { batchEnd = searchEnd }
+        keys, err := w.deriveValidatorKeys(batchStart, batchEnd)
+        if err != nil {
+            return nil, err
+        }
+
+        // Check keys & extend search
+        for ki, key := range keys {
+            pubkey := rptypes.BytesToValidatorPubkey(key.PublicKey().Marshal())
+            if !remaining[pubkey] { continue }
+            index := batchStart + uint(ki)
+            indices[pubkey] = index
+            delete(remaining, pubkey)
+            if index + 1 + searchWindow > searchEnd {
+                searchEnd = index + 1 + searchWindow
+            }
+        }
+
+    }
+
+    // Return
+    return indices, nil
+
+}
+
+
+// Derive validator keys over an index range in parallel
+func (w *Wallet) deriveValidatorKeys(start, end uint) ([]*eth2types.BLSPrivateKey, error) {
+
+    // Initialize BLS support
+    initializeBLS()
+
+    // Derive keys in batches
+    keys := make([]*eth2types.BLSPrivateKey, end - start)
+    for batchStart := start; batchStart < end; batchStart += ValidatorKeyDeriveBatchSize {
+
+        // Get batch end index
+        batchEnd := batchStart + ValidatorKeyDeriveBatchSize
+        if batchEnd > end { batchEnd = end }
+
+        // Derive keys not already cached
+        var wg errgroup.Group
+        for index := batchStart; index < batchEnd; index++ {
+            index := index
+            if key, ok := w.validatorKeys[index]; ok {
+                keys[index - start] = key
+                continue
+            }
+            wg.Go(func() error {
+                key, _, err := deriveValidatorKey(w.seed, index)
+                if err == nil { keys[index - start] = key }
+                return err
+            })
+        }
+        if err := wg.Wait(); err != nil {
+            return nil, err
+        }
+
+        // Cache keys
+        for index := batchStart; index < batchEnd; index++ {
+            w.validatorKeys[index] = keys[index - start]
+        }
+
+    }
+
+    // Return
+    return keys, nil
+
+}
+
+
 // Get a validator private key by index
 func (w *Wallet) getValidatorPrivateKey(index uint) (*eth2types.BLSPrivateKey, string, error) {
 
-    // Get derivation path
-    derivationPath := fmt.Sprintf(ValidatorKeyPath, index)
-
     // Check for cached validator key
     if validatorKey, ok := w.validatorKeys[index]; ok {
-        return validatorKey, derivationPath, nil
+        return validatorKey, fmt.Sprintf(ValidatorKeyPath, index), nil
     }
 
     // Initialize BLS support
     initializeBLS()
 
     // Get private key
-    privateKey, err := eth2util.PrivateKeyFromSeedAndPath(w.seed, derivationPath)
+    privateKey, derivationPath, err := deriveValidatorKey(w.seed, index)
     if err != nil {
-        return nil, "", fmt.Errorf("Could not get validator %d private key: %w", index, err)
+        return nil, "", err
     }
 
     // Cache validator key
@@ -200,6 +438,17 @@ func (w *Wallet) getValidatorPrivateKey(index uint) (*eth2types.BLSPrivateKey, s
 }
 
 
+// Derive a validator private key by index from a wallet seed
+func deriveValidatorKey(seed []byte, index uint) (*eth2types.BLSPrivateKey, string, error) {
+    derivationPath := fmt.Sprintf(ValidatorKeyPath, index)
+    privateKey, err := eth2util.PrivateKeyFromSeedAndPath(seed, derivationPath)
+    if err != nil {
+        return nil, "", fmt.Errorf("Could not get validator %d private key: %w", index, err)
+    }
+    return privateKey, derivationPath, nil
+}
+
+
 // Initialize BLS support
 var initBLS sync.Once
 func initializeBLS() {
@@ -17,6 +17,7 @@ import (
 
     "github.com/rocket-pool/smartnode/shared/services/passwords"
     "github.com/rocket-pool/smartnode/shared/services/wallet/keystore"
+    "github.com/rocket-pool/smartnode/shared/utils/files"
 )
 
 
@@ -49,7 +50,11 @@ type Wallet struct {
 
     // Validator key caches
     validatorKeys map[uint]*eth2types.BLSPrivateKey
+
+    // Validator key index, decrypted on first use
     validatorKeyIndices map[string]uint
+    validatorKeyIndicesLoaded bool
+    validatorKeyIndicesUpdated bool
 
     // Keystores
     keystores map[string]keystore.Keystore
@@ -68,6 +73,8 @@ type walletStore struct {
     Version uint                    `json:"version"`
     UUID uuid.UUID                  `json:"uuid"`
     NextAccount uint                `json:"next_account"`
+    PendingAccounts []uint          `json:"pending_accounts,omitempty"`
+    KeyIndex map[string]interface{} `json:"validator_key_index,omitempty"`
 }
 
 
@@ -208,6 +215,13 @@ func (w *Wallet) Save() error {
         return errors.New("Wallet is not initialized")
     }
 
+    // Encrypt validator key index if updated
+    if w.validatorKeyIndicesUpdated {
+        if err := w.encryptValidatorKeyIndices(); err != nil {
+            return err
+        }
+    }
+
     // Encode wallet store
     wsBytes, err := json.Marshal(w.ws)
     if err != nil {
@@ -215,7 +229,7 @@ func (w *Wallet) Save() error {
     }
 
     // Write wallet store to disk
-    if err := ioutil.WriteFile(w.walletPath, wsBytes, FileMode); err != nil {
+    if err := files.WriteFileAtomic(w.walletPath, wsBytes, FileMode); err != nil {
         return fmt.Errorf("Could not write wallet to disk: %w", err)
     }
 
@@ -258,6 +272,10 @@ func (w *Wallet) loadStore() (bool, error) {
         return false, fmt.Errorf("Could not create wallet master key: %w", err)
     }
 
+    // Reset validator key index
+    w.validatorKeyIndices = map[string]uint{}
+    w.validatorKeyIndicesLoaded = false
+
     // Return
     return true, nil
 
@@ -298,6 +316,10 @@ func (w *Wallet) initializeStore(mnemonic string) error {
         NextAccount: 0,
     }
 
+    // Reset validator key index
+    w.validatorKeyIndices = map[string]uint{}
+    w.validatorKeyIndicesLoaded = true
+
     // Return
     return nil
 
@@ -39,6 +39,26 @@ type ValidatorDetails struct {
 }
 
 
+type MinipoolPerformanceResponse struct {
+    Status string                   `json:"status"`
+    Error string                    `json:"error"`
+    StartEpoch uint64               `json:"startEpoch"`
+    EndEpoch uint64                 `json:"endEpoch"`
+    Minipools []MinipoolPerformance `json:"minipools"`
+}
+type MinipoolPerformance struct {
+    Address common.Address                  `json:"address"`
+    ValidatorPubkey types.ValidatorPubkey   `json:"validatorPubkey"`
+    AttestationDuties uint64                `json:"attestationDuties"`
+    MissedAttestations uint64               `json:"missedAttestations"`
+    Effectiveness float64                   `json:"effectiveness"`
+    ProposalDuties uint64                   `json:"proposalDuties"`
+    MissedProposals uint64                  `json:"missedProposals"`
+    StartBalance *big.Int                   `json:"startBalance"`
+    EndBalance *big.Int                     `json:"endBalance"`
+}
+
+
 type CanRefundMinipoolResponse struct {
     Status string                   `json:"status"`
     Error string                    `json:"error"`
@@ -29,6 +29,19 @@ type NodeStatusResponse struct {
 }
 
 
+type NodeEventResponse struct {
+    Status string                   `json:"status"`
+    Error string                    `json:"error"`
+    Topic string                    `json:"topic"`
+    Slot uint64                     `json:"slot"`
+    Epoch uint64                    `json:"epoch"`
+    BlockRoot common.Hash           `json:"blockRoot"`
+    Depth uint64                    `json:"depth"`
+    ValidatorIndex uint64           `json:"validatorIndex"`
+    MinipoolAddress common.Address  `json:"minipoolAddress"`
+}
+
+
 type CanRegisterNodeResponse struct {
     Status string                   `json:"status"`
     Error string                    `json:"error"`
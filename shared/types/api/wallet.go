@@ -52,3 +52,45 @@ type ExportWalletResponse struct {
     AccountPrivateKey string                `json:"accountPrivateKey"`
 }
 
+
+type PrunableValidatorKey struct {
+    Pubkey types.ValidatorPubkey            `json:"pubkey"`
+    MinipoolAddress common.Address          `json:"minipoolAddress"`
+    Reason string                           `json:"reason"`
+}
+type GetPrunableValidatorKeysResponse struct {
+    Status string                           `json:"status"`
+    Error string                            `json:"error"`
+    ValidatorKeys []PrunableValidatorKey    `json:"validatorKeys"`
+}
+type PruneWalletResponse struct {
+    Status string                           `json:"status"`
+    Error string                            `json:"error"`
+    ValidatorKeys []PrunableValidatorKey    `json:"validatorKeys"`
+}
+
+
+type ValidatorKeyAudit struct {
+    Pubkey types.ValidatorPubkey            `json:"pubkey"`
+    Index uint                              `json:"index"`
+    DerivationPath string                   `json:"derivationPath"`
+    Derived bool                            `json:"derived"`
+    Minipool bool                           `json:"minipool"`
+    Keystores []KeystoreKeyAudit            `json:"keystores"`
+}
+type KeystoreKeyAudit struct {
+    Keystore string                         `json:"keystore"`
+    Status string                           `json:"status"`
+    Detail string                           `json:"detail"`
+}
+type AuditWalletResponse struct {
+    Status string                           `json:"status"`
+    Error string                            `json:"error"`
+    ValidatorKeys []ValidatorKeyAudit       `json:"validatorKeys"`
+}
+type RepairWalletResponse struct {
+    Status string                           `json:"status"`
+    Error string                            `json:"error"`
+    ValidatorKeys []ValidatorKeyAudit       `json:"validatorKeys"`
+}
+
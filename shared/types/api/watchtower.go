@@ -0,0 +1,12 @@
+package api
+
+import (
+    "github.com/rocket-pool/smartnode/shared/services/database"
+)
+
+
+type WatchtowerHistoryResponse struct {
+    Status string                       `json:"status"`
+    Error string                        `json:"error"`
+    Submissions []database.Submission   `json:"submissions"`
+}
@@ -6,10 +6,18 @@ import (
     "fmt"
     "reflect"
 
+    "github.com/fatih/color"
+
     "github.com/rocket-pool/smartnode/shared/types/api"
+    "github.com/rocket-pool/smartnode/shared/utils/log"
 )
 
 
+// Logger for API errors
+// Log output is written to stderr so that responses printed to stdout are not affected
+var apiLog = log.NewLogger(color.FgRed)
+
+
 // Print an API response
 // response must be a pointer to a struct type with Error and Status string fields
 func PrintResponse(response interface{}, responseError error) {
@@ -35,9 +43,10 @@ func PrintResponse(response interface{}, responseError error) {
         return
     }
 
-    // Populate error
+    // Populate & log error
     if responseError != nil {
         ef.SetString(responseError.Error())
+        apiLog.Error("API command failed", "response", r.Type().Elem().Name(), "error", responseError)
     }
 
     // Set status
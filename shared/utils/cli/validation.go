@@ -8,6 +8,7 @@ import (
     "strings"
 
     "github.com/ethereum/go-ethereum/common"
+    rptypes "github.com/rocket-pool/rocketpool-go/types"
     "github.com/tyler-smith/go-bip39"
     "github.com/urfave/cli"
 
@@ -38,6 +39,25 @@ func ValidateAddress(name, value string) (common.Address, error) {
 }
 
 
+// Validate a comma-separated list of validator pubkeys
+func ValidateValidatorPubkeys(name, value string) ([]rptypes.ValidatorPubkey, error) {
+    values := strings.Split(value, ",")
+    pubkeys := make([]rptypes.ValidatorPubkey, len(values))
+    for vi, val := range values {
+        val = strings.TrimPrefix(strings.TrimSpace(val), "0x")
+        if len(val) != rptypes.ValidatorPubkeyLength * 2 {
+            return nil, fmt.Errorf("Invalid %s '%s'", name, value)
+        }
+        pubkey, err := rptypes.HexToValidatorPubkey(val)
+        if err != nil {
+            return nil, fmt.Errorf("Invalid %s '%s'", name, value)
+        }
+        pubkeys[vi] = pubkey
+    }
+    return pubkeys, nil
+}
+
+
 // Validate a wei amount
 func ValidateWeiAmount(name, value string) (*big.Int, error) {
     val := new(big.Int)
@@ -88,6 +108,16 @@ func ValidateTokenType(name, value string) (string, error) {
 }
 
 
+// Validate a positive unsigned integer
+func ValidatePositiveUint(name, value string) (uint64, error) {
+    val, err := strconv.ParseUint(value, 10, 64)
+    if err != nil || val == 0 {
+        return 0, fmt.Errorf("Invalid %s '%s' - must be a positive integer", name, value)
+    }
+    return val, nil
+}
+
+
 //
 // Command specific types
 //
@@ -0,0 +1,60 @@
+package eth2
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+
+    "github.com/ethereum/go-ethereum/common"
+    rptypes "github.com/rocket-pool/rocketpool-go/types"
+)
+
+
+// EIP-3076 slashing protection interchange format version
+const InterchangeFormatVersion = "5"
+
+
+// EIP-3076 slashing protection interchange data
+type Interchange struct {
+    Metadata struct {
+        InterchangeFormatVersion string     `json:"interchange_format_version"`
+        GenesisValidatorsRoot common.Hash   `json:"genesis_validators_root"`
+    }                                       `json:"metadata"`
+    Data []InterchangeValidator             `json:"data"`
+}
+type InterchangeValidator struct {
+    Pubkey rptypes.ValidatorPubkey          `json:"pubkey"`
+    SignedBlocks []struct {
+        Slot string                         `json:"slot"`
+        SigningRoot string                  `json:"signing_root,omitempty"`
+    }                                       `json:"signed_blocks"`
+    SignedAttestations []struct {
+        SourceEpoch string                  `json:"source_epoch"`
+        TargetEpoch string                  `json:"target_epoch"`
+        SigningRoot string                  `json:"signing_root,omitempty"`
+    }                                       `json:"signed_attestations"`
+}
+
+
+// Parse and validate EIP-3076 slashing protection interchange data
+func ParseInterchange(data []byte) (Interchange, error) {
+
+    // Decode interchange data
+    var interchange Interchange
+    if err := json.Unmarshal(data, &interchange); err != nil {
+        return Interchange{}, fmt.Errorf("Could not decode slashing protection interchange data: %w", err)
+    }
+
+    // Validate metadata
+    if interchange.Metadata.InterchangeFormatVersion != InterchangeFormatVersion {
+        return Interchange{}, fmt.Errorf("Unsupported slashing protection interchange format version '%s'", interchange.Metadata.InterchangeFormatVersion)
+    }
+    if interchange.Metadata.GenesisValidatorsRoot == (common.Hash{}) {
+        return Interchange{}, errors.New("Slashing protection interchange data does not contain a genesis validators root")
+    }
+
+    // Return
+    return interchange, nil
+
+}
+
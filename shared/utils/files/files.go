@@ -0,0 +1,52 @@
+package files
+
+import (
+    "io/ioutil"
+    "os"
+    "path/filepath"
+)
+
+
+// Write a file atomically
+// Data is written & synced to a temporary file in the same folder, which then replaces the file, so the file is never left partially written
+func WriteFileAtomic(path string, data []byte, mode os.FileMode) error {
+
+    // Create temporary file
+    tmpFile, err := ioutil.TempFile(filepath.Dir(path), "." + filepath.Base(path) + ".tmp")
+    if err != nil {
+        return err
+    }
+    tmpPath := tmpFile.Name()
+
+    // Write & sync data
+    if err := tmpFile.Chmod(mode); err != nil {
+        tmpFile.Close()
+        os.Remove(tmpPath)
+        return err
+    }
+    if _, err := tmpFile.Write(data); err != nil {
+        tmpFile.Close()
+        os.Remove(tmpPath)
+        return err
+    }
+    if err := tmpFile.Sync(); err != nil {
+        tmpFile.Close()
+        os.Remove(tmpPath)
+        return err
+    }
+    if err := tmpFile.Close(); err != nil {
+        os.Remove(tmpPath)
+        return err
+    }
+
+    // Replace file
+    if err := os.Rename(tmpPath, path); err != nil {
+        os.Remove(tmpPath)
+        return err
+    }
+
+    // Return
+    return nil
+
+}
+
@@ -1,50 +1,254 @@
 package log
 
 import (
-    "log"
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+    "sync"
+    "time"
 
     "github.com/fatih/color"
 )
 
 
-// Logger with ANSI color output
-type ColorLogger struct {
+// Log levels
+type Level int
+const (
+    LevelDebug Level = iota
+    LevelInfo
+    LevelWarn
+    LevelError
+)
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+
+// Output formats
+const (
+    FormatText = "text"
+    FormatJSON = "json"
+)
+
+
+// Timestamp formats
+const (
+    textTimeFormat = "2006/01/02 15:04:05"
+    jsonTimeFormat = time.RFC3339
+)
+
+
+// Global logger settings
+var (
+    minLevel = LevelInfo
+    jsonFormat = false
+    output io.Writer = os.Stderr
+    settingsLock sync.RWMutex
+    outputLock sync.Mutex
+)
+
+
+// Get a level's name
+func (l Level) String() string {
+    if int(l) >= 0 && int(l) < len(levelNames) {
+        return levelNames[l]
+    }
+    return fmt.Sprintf("level(%d)", int(l))
+}
+
+
+// Parse a level from its name
+func ParseLevel(name string) (Level, error) {
+    for li, levelName := range levelNames {
+        if strings.EqualFold(name, levelName) {
+            return Level(li), nil
+        }
+    }
+    return LevelInfo, fmt.Errorf("Unknown log level '%s'", name)
+}
+
+
+// Configure the minimum level and output format for all loggers
+// Empty values leave the current setting unchanged
+func Configure(levelName string, format string) error {
+
+    // Parse settings
+    level := GetLevel()
+    if levelName != "" {
+        var err error
+        level, err = ParseLevel(levelName)
+        if err != nil {
+            return err
+        }
+    }
+    useJSON := IsJSON()
+    switch strings.ToLower(format) {
+        case "": // Unchanged
+        case FormatText: useJSON = false
+        case FormatJSON: useJSON = true
+        default: return fmt.Errorf("Unknown log format '%s'", format)
+    }
+
+    // Update settings
+    settingsLock.Lock()
+    defer settingsLock.Unlock()
+    minLevel = level
+    jsonFormat = useJSON
+    return nil
+
+}
+
+
+// Get the minimum level for all loggers
+func GetLevel() Level {
+    settingsLock.RLock()
+    defer settingsLock.RUnlock()
+    return minLevel
+}
+
+
+// Check whether loggers are writing JSON output
+func IsJSON() bool {
+    settingsLock.RLock()
+    defer settingsLock.RUnlock()
+    return jsonFormat
+}
+
+
+// Levelled logger with key-value fields
+// Text output is colored; JSON output is written as one object per line
+type Logger struct {
     Color color.Attribute
     sprintFunc func(a ...interface{}) string
-    sprintfFunc func(format string, a ...interface{}) string
+    fields []interface{}
 }
 
 
-// Create new color logger
-func NewColorLogger(colorAttr color.Attribute) ColorLogger {
-    return ColorLogger{
+// Create new logger
+func NewLogger(colorAttr color.Attribute) Logger {
+    return Logger{
         Color: colorAttr,
         sprintFunc: color.New(colorAttr).SprintFunc(),
-        sprintfFunc: color.New(colorAttr).SprintfFunc(),
+        fields: []interface{}{},
     }
 }
 
 
-// Print values
-func (l *ColorLogger) Print(v ...interface{}) {
-    log.Print(l.sprintFunc(v...))
+// Create a copy of the logger with additional fields
+func (l Logger) With(keyvals ...interface{}) Logger {
+    fields := make([]interface{}, 0, len(l.fields) + len(keyvals))
+    fields = append(fields, l.fields...)
+    fields = append(fields, keyvals...)
+    l.fields = fields
+    return l
 }
 
 
-// Print values with a newline
-func (l *ColorLogger) Println(v ...interface{}) {
-    log.Println(l.sprintFunc(v...))
+// Log a message at each level, with optional alternating key-value fields
+func (l Logger) Debug(msg string, keyvals ...interface{}) { l.write(LevelDebug, msg, keyvals) }
+func (l Logger) Info(msg string, keyvals ...interface{})  { l.write(LevelInfo, msg, keyvals) }
+func (l Logger) Warn(msg string, keyvals ...interface{})  { l.write(LevelWarn, msg, keyvals) }
+func (l Logger) Error(msg string, keyvals ...interface{}) { l.write(LevelError, msg, keyvals) }
+
+
+// Write a log entry if its level is enabled
+func (l Logger) write(level Level, msg string, keyvals []interface{}) {
+
+    // Get settings
+    settingsLock.RLock()
+    enabled := (level >= minLevel)
+    useJSON := jsonFormat
+    settingsLock.RUnlock()
+    if !enabled {
+        return
+    }
+
+    // Get fields
+    fields := make([]interface{}, 0, len(l.fields) + len(keyvals))
+    fields = append(fields, l.fields...)
+    fields = append(fields, keyvals...)
+    if len(fields) % 2 != 0 {
+        fields = append(fields, nil)
+    }
+
+    // Format entry
+    var entry []byte
+    if useJSON {
+        entry = formatJSON(time.Now(), level, msg, fields)
+    } else {
+        entry = l.formatText(time.Now(), level, msg, fields)
+    }
+
+    // Write entry
+    outputLock.Lock()
+    defer outputLock.Unlock()
+    output.Write(entry)
+
 }
 
 
-// Print a formatted string
-func (l *ColorLogger) Printf(format string, v ...interface{}) {
-    log.Print(l.sprintfFunc(format, v...))
+// Format a log entry as colored text
+func (l Logger) formatText(t time.Time, level Level, msg string, fields []interface{}) []byte {
+    var line strings.Builder
+    if level != LevelInfo {
+        line.WriteString(strings.ToUpper(level.String()))
+        line.WriteString(": ")
+    }
+    line.WriteString(msg)
+    for fi := 0; fi < len(fields); fi += 2 {
+        value := fmt.Sprint(formatValue(fields[fi + 1]))
+        if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+            value = fmt.Sprintf("%q", value)
+        }
+        line.WriteString(fmt.Sprintf(" %v=%s", fields[fi], value))
+    }
+    return []byte(t.Format(textTimeFormat) + " " + l.sprintFunc(line.String()) + "\n")
 }
 
 
-// Print a formatted string with a newline
-func (l *ColorLogger) Printlnf(format string, v ...interface{}) {
-    log.Println(l.sprintfFunc(format, v...))
+// Format a log entry as a JSON object, preserving field order
+func formatJSON(t time.Time, level Level, msg string, fields []interface{}) []byte {
+    var buf bytes.Buffer
+    buf.WriteString("{")
+    writeJSONField(&buf, "time", t.Format(jsonTimeFormat), true)
+    writeJSONField(&buf, "level", level.String(), false)
+    writeJSONField(&buf, "msg", msg, false)
+    for fi := 0; fi < len(fields); fi += 2 {
+        writeJSONField(&buf, fmt.Sprint(fields[fi]), formatValue(fields[fi + 1]), false)
+    }
+    buf.WriteString("}\n")
+    return buf.Bytes()
 }
 
+
+// Write a key-value pair to a JSON object buffer
+func writeJSONField(buf *bytes.Buffer, key string, value interface{}, first bool) {
+    keyBytes, _ := json.Marshal(key)
+    valueBytes, err := json.Marshal(value)
+    if err != nil {
+        valueBytes, _ = json.Marshal(fmt.Sprint(value))
+    }
+    if !first {
+        buf.WriteString(",")
+    }
+    buf.Write(keyBytes)
+    buf.WriteString(":")
+    buf.Write(valueBytes)
+}
+
+
+// Format a field value for output
+// Falls back to fmt formatting if the value's own formatting panics (e.g. nil pointers)
+func formatValue(value interface{}) (formatted interface{}) {
+    defer func() {
+        if r := recover(); r != nil {
+            formatted = fmt.Sprint(value)
+        }
+    }()
+    switch v := value.(type) {
+        case error: return v.Error()
+        case fmt.Stringer: return v.String()
+        default: return v
+    }
+}
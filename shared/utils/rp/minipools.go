@@ -1,6 +1,9 @@
 package rp
 
 import (
+    "context"
+    "math/big"
+
     "github.com/ethereum/go-ethereum/accounts/abi/bind"
     "github.com/ethereum/go-ethereum/common"
     "github.com/rocket-pool/rocketpool-go/minipool"
@@ -9,6 +12,7 @@ import (
     "golang.org/x/sync/errgroup"
 
     "github.com/rocket-pool/smartnode/shared/services/beacon"
+    "github.com/rocket-pool/smartnode/shared/services/database"
 )
 
 
@@ -16,8 +20,17 @@ import (
 const MinipoolPubkeyBatchSize = 50
 
 
+// Minipool deposit details
+type MinipoolDeposits struct {
+    NodeDepositBalance *big.Int
+    UserDepositBalance *big.Int
+    UserDepositTime uint64
+}
+
+
 // Get minipool validator statuses
-func GetMinipoolValidators(rp *rocketpool.RocketPool, bc beacon.Client, addresses []common.Address, callOpts *bind.CallOpts, validatorStatusOpts *beacon.ValidatorStatusOptions) (map[common.Address]beacon.ValidatorStatus, error) {
+// Pubkeys are read from & saved to the database cache if a database is provided
+func GetMinipoolValidators(rp *rocketpool.RocketPool, bc beacon.Client, db *database.Database, addresses []common.Address, callOpts *bind.CallOpts, validatorStatusOpts *beacon.ValidatorStatusOptions) (map[common.Address]beacon.ValidatorStatus, error) {
 
     // Load minipool validator pubkeys in batches
     pubkeys := make([]types.ValidatorPubkey, len(addresses))
@@ -34,7 +47,7 @@ func GetMinipoolValidators(rp *rocketpool.RocketPool, bc beacon.Client, addresse
             mi := mi
             wg.Go(func() error {
                 address := addresses[mi]
-                pubkey, err := minipool.GetMinipoolPubkey(rp, address, callOpts)
+                pubkey, err := getMinipoolPubkey(rp, db, address, callOpts)
                 if err == nil { pubkeys[mi] = pubkey }
                 return err
             })
@@ -45,6 +58,13 @@ func GetMinipoolValidators(rp *rocketpool.RocketPool, bc beacon.Client, addresse
 
     }
 
+    // Save cached pubkeys
+    if db != nil {
+        if err := db.SaveMinipools(); err != nil {
+            return map[common.Address]beacon.ValidatorStatus{}, err
+        }
+    }
+
     // Get validator statuses
     statuses, err := bc.GetValidatorStatuses(pubkeys, validatorStatusOpts)
     if err != nil {
@@ -66,3 +86,127 @@ func GetMinipoolValidators(rp *rocketpool.RocketPool, bc beacon.Client, addresse
 
 }
 
+
+// Get a minipool's deposit balances & user deposit time
+// Deposits are final once a minipool is withdrawable, or staking with its user deposit assigned, so are read from & cached in the database if a database is provided
+// Full deposit minipools may be assigned a user deposit while staking, so their deposits are re-read until assigned
+// Cached deposits are only used for queries at or after the block they were known to be final at
+func GetMinipoolDeposits(mp *minipool.Minipool, db *database.Database, status types.MinipoolStatus, callOpts *bind.CallOpts) (MinipoolDeposits, error) {
+
+    // Get query block; nil for the latest block
+    var queryBlock *big.Int
+    if callOpts != nil {
+        queryBlock = callOpts.BlockNumber
+    }
+
+    // Get cached deposits; entries without a user deposit time are only final for withdrawable minipools
+    if db != nil && (status == types.Staking || status == types.Withdrawable) {
+        details, ok, err := db.GetMinipoolDetails(mp.Address)
+        if err != nil {
+            return MinipoolDeposits{}, err
+        }
+        cacheValid := (ok && details.DepositsFinal && details.DepositsFinalBlock != 0 && (queryBlock == nil || queryBlock.Uint64() >= details.DepositsFinalBlock))
+        if cacheValid && (details.UserDepositTime != 0 || status == types.Withdrawable) {
+            return MinipoolDeposits{
+                NodeDepositBalance: details.NodeDepositBalance,
+                UserDepositBalance: details.UserDepositBalance,
+                UserDepositTime: details.UserDepositTime,
+            }, nil
+        }
+    }
+
+    // Data
+    var wg errgroup.Group
+    var deposits MinipoolDeposits
+    var userDepositAssigned bool
+
+    // Load data
+    wg.Go(func() error {
+        var err error
+        deposits.NodeDepositBalance, err = mp.GetNodeDepositBalance(callOpts)
+        return err
+    })
+    wg.Go(func() error {
+        var err error
+        deposits.UserDepositBalance, err = mp.GetUserDepositBalance(callOpts)
+        return err
+    })
+    wg.Go(func() error {
+        var err error
+        userDepositAssigned, err = mp.GetUserDepositAssigned(callOpts)
+        return err
+    })
+    wg.Go(func() error {
+        userDepositAssignedTime, err := mp.GetUserDepositAssignedTime(callOpts)
+        if err == nil {
+            deposits.UserDepositTime = uint64(userDepositAssignedTime.Unix())
+        }
+        return err
+    })
+
+    // Wait for data
+    if err := wg.Wait(); err != nil {
+        return MinipoolDeposits{}, err
+    }
+
+    // Cache final deposits
+    depositsFinal := (status == types.Withdrawable || (status == types.Staking && userDepositAssigned))
+    if db != nil && depositsFinal {
+
+        // Get the block deposits were read at; the latest block is read afterwards, so is no earlier
+        var finalBlock uint64
+        if queryBlock != nil {
+            finalBlock = queryBlock.Uint64()
+        } else {
+            header, err := mp.RocketPool.Client.HeaderByNumber(context.Background(), nil)
+            if err != nil {
+                return MinipoolDeposits{}, err
+            }
+            finalBlock = header.Number.Uint64()
+        }
+
+        // Cache deposits
+        if err := db.SetMinipoolDeposits(mp.Address, deposits.NodeDepositBalance, deposits.UserDepositBalance, deposits.UserDepositTime, finalBlock); err != nil {
+            return MinipoolDeposits{}, err
+        }
+
+    }
+
+    // Return
+    return deposits, nil
+
+}
+
+
+// Get a minipool's validator pubkey
+// Pubkeys do not change once set, so are read from & cached in the database if a database is provided
+func getMinipoolPubkey(rp *rocketpool.RocketPool, db *database.Database, address common.Address, callOpts *bind.CallOpts) (types.ValidatorPubkey, error) {
+
+    // Get cached pubkey
+    if db != nil {
+        details, ok, err := db.GetMinipoolDetails(address)
+        if err != nil {
+            return types.ValidatorPubkey{}, err
+        }
+        if ok && details.ValidatorPubkey != (types.ValidatorPubkey{}) {
+            return details.ValidatorPubkey, nil
+        }
+    }
+
+    // Load pubkey
+    pubkey, err := minipool.GetMinipoolPubkey(rp, address, callOpts)
+    if err != nil {
+        return types.ValidatorPubkey{}, err
+    }
+
+    // Cache pubkey once set
+    if db != nil && pubkey != (types.ValidatorPubkey{}) {
+        if err := db.SetMinipoolPubkey(address, pubkey); err != nil {
+            return types.ValidatorPubkey{}, err
+        }
+    }
+
+    // Return
+    return pubkey, nil
+
+}
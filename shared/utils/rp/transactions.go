@@ -0,0 +1,47 @@
+package rp
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/ethereum/go-ethereum"
+    "github.com/ethereum/go-ethereum/accounts/abi/bind"
+    "github.com/rocket-pool/rocketpool-go/rocketpool"
+)
+
+
+// Simulate a contract transaction via eth_call & gas estimation without sending it
+// Returns the estimated gas required, or an error if the transaction would revert
+func SimulateTransaction(contract *rocketpool.Contract, opts *bind.TransactOpts, method string, params ...interface{}) (uint64, error) {
+
+    // Encode input data
+    input, err := contract.ABI.Pack(method, params...)
+    if err != nil {
+        return 0, fmt.Errorf("Could not encode input data: %w", err)
+    }
+
+    // Get call message
+    msg := ethereum.CallMsg{
+        From: opts.From,
+        To: contract.Address,
+        Gas: opts.GasLimit,
+        GasPrice: opts.GasPrice,
+        Value: opts.Value,
+        Data: input,
+    }
+
+    // Execute call
+    if _, err := contract.Client.CallContract(context.Background(), msg, nil); err != nil {
+        return 0, fmt.Errorf("Simulated %s call failed: %w", method, err)
+    }
+
+    // Estimate gas
+    gasEstimate, err := contract.Client.EstimateGas(context.Background(), msg)
+    if err != nil {
+        return 0, fmt.Errorf("Could not estimate gas needed: %w", err)
+    }
+
+    // Return
+    return gasEstimate, nil
+
+}
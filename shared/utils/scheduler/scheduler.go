@@ -0,0 +1,178 @@
+package scheduler
+
+import (
+    "math/rand"
+    "sync"
+    "time"
+
+    "github.com/rocket-pool/smartnode/shared/services/metrics"
+    "github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+
+// Task settings
+type TaskSettings struct {
+    Interval time.Duration
+    Jitter time.Duration
+    Timeout time.Duration
+    MinTriggerInterval time.Duration // Minimum time since the last run before a trigger will run the task
+}
+
+
+// Scheduled task
+type task struct {
+    name string
+    run func() error
+    settings TaskSettings
+    trigger chan struct{}
+    lock sync.Mutex
+    running bool
+    lastRun time.Time
+}
+
+
+// Task scheduler
+// Runs each task on its own interval or when triggered; overlapping runs of the same task are skipped
+type Scheduler struct {
+    tasks []*task
+    tasksByName map[string]*task
+    log log.Logger
+    rand *rand.Rand
+    randLock sync.Mutex
+}
+
+
+// Create new scheduler
+func NewScheduler(logger log.Logger) *Scheduler {
+    return &Scheduler{
+        tasks: []*task{},
+        tasksByName: map[string]*task{},
+        log: logger,
+        rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+    }
+}
+
+
+// Add a task to the scheduler
+func (s *Scheduler) AddTask(name string, run func() error, settings TaskSettings) {
+    t := &task{
+        name: name,
+        run: run,
+        settings: settings,
+        trigger: make(chan struct{}, 1),
+    }
+    s.tasks = append(s.tasks, t)
+    s.tasksByName[name] = t
+}
+
+
+// Trigger a task to run ahead of its next scheduled interval
+// Triggers received while the task is running are coalesced into a single run after it completes
+func (s *Scheduler) Trigger(name string) {
+
+    // Get task
+    t, ok := s.tasksByName[name]
+    if !ok {
+        s.log.Error("Could not trigger unknown task", "task", name)
+        return
+    }
+
+    // Check minimum trigger interval
+    t.lock.Lock()
+    lastRun := t.lastRun
+    t.lock.Unlock()
+    if time.Since(lastRun) < t.settings.MinTriggerInterval {
+        return
+    }
+
+    // Send trigger without blocking
+    select {
+        case t.trigger <- struct{}{}:
+        default:
+    }
+
+}
+
+
+// Start running all tasks
+func (s *Scheduler) Start() {
+    for _, t := range s.tasks {
+        t := t
+        go (func() {
+            s.wait(t, s.getJitter(t))
+            for {
+                s.runTask(t)
+                s.wait(t, t.settings.Interval + s.getJitter(t))
+            }
+        })()
+    }
+}
+
+
+// Run a task if it is not already running
+func (s *Scheduler) runTask(t *task) {
+
+    // Check & set running status
+    t.lock.Lock()
+    if t.running {
+        t.lock.Unlock()
+        s.log.Warn("Task is still running, skipping...", "task", t.name)
+        return
+    }
+    t.running = true
+    t.lastRun = time.Now()
+    t.lock.Unlock()
+
+    // Run task; clear running status & record metrics on completion
+    done := make(chan error, 1)
+    go (func() {
+        startTime := time.Now()
+        s.log.Debug("Running task", "task", t.name)
+        err := t.run()
+        s.log.Debug("Task completed", "task", t.name, "duration", time.Since(startTime))
+        metrics.RecordTaskRun(t.name, time.Since(startTime), (err != nil))
+        t.lock.Lock()
+        t.running = false
+        t.lock.Unlock()
+        done <- err
+    })()
+
+    // Wait for task completion or timeout
+    var timeout <-chan time.Time
+    if t.settings.Timeout > 0 {
+        timer := time.NewTimer(t.settings.Timeout)
+        defer timer.Stop()
+        timeout = timer.C
+    }
+    select {
+        case err := <-done:
+            if err != nil {
+                s.log.Error("Task failed", "task", t.name, "error", err)
+            }
+        case <-timeout:
+            s.log.Error("Task timed out; it will not be run again until it completes", "task", t.name, "timeout", t.settings.Timeout)
+    }
+
+}
+
+
+// Wait for a task's next run, until a duration has elapsed or the task is triggered
+func (s *Scheduler) wait(t *task, duration time.Duration) {
+    timer := time.NewTimer(duration)
+    defer timer.Stop()
+    select {
+        case <-timer.C:
+        case <-t.trigger:
+    }
+}
+
+
+// Get a random jitter duration for a task
+func (s *Scheduler) getJitter(t *task) time.Duration {
+    if t.settings.Jitter <= 0 {
+        return 0
+    }
+    s.randLock.Lock()
+    defer s.randLock.Unlock()
+    return time.Duration(s.rand.Int63n(int64(t.settings.Jitter)))
+}
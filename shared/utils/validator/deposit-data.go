@@ -1,6 +1,9 @@
 package validator
 
 import (
+    "errors"
+    "fmt"
+
     "github.com/ethereum/go-ethereum/common"
     "github.com/prysmaticlabs/go-ssz"
     eth2types "github.com/wealdtech/go-eth2-types/v2"
@@ -32,17 +35,8 @@ func GetDepositData(validatorKey *eth2types.BLSPrivateKey, withdrawalCredentials
         Amount: DepositAmount,
     }
 
-    // Get signing root
-    sr, err := ssz.SigningRoot(depositData)
-    if err != nil {
-        return DepositData{}, common.Hash{}, err
-    }
-
     // Get signing root with domain
-    srWithDomain, err := ssz.HashTreeRoot(signingRoot{
-        ObjectRoot: sr[:],
-        Domain: eth2types.Domain(eth2types.DomainDeposit, eth2Config.GenesisForkVersion, eth2types.ZeroGenesisValidatorsRoot),
-    })
+    srWithDomain, err := getDepositDataSigningRoot(depositData, eth2Config)
     if err != nil {
         return DepositData{}, common.Hash{}, err
     }
@@ -61,3 +55,51 @@ func GetDepositData(validatorKey *eth2types.BLSPrivateKey, withdrawalCredentials
 
 }
 
+
+// Verify a deposit data signature against its public key and the genesis fork version
+func VerifyDepositData(depositData DepositData, eth2Config beacon.Eth2Config) error {
+
+    // Get public key & signature
+    pubkey, err := eth2types.BLSPublicKeyFromBytes(depositData.PublicKey)
+    if err != nil {
+        return fmt.Errorf("Invalid deposit data public key: %w", err)
+    }
+    signature, err := eth2types.BLSSignatureFromBytes(depositData.Signature)
+    if err != nil {
+        return fmt.Errorf("Invalid deposit data signature: %w", err)
+    }
+
+    // Get signing root with domain
+    srWithDomain, err := getDepositDataSigningRoot(depositData, eth2Config)
+    if err != nil {
+        return err
+    }
+
+    // Verify signature
+    if !signature.Verify(srWithDomain[:], pubkey) {
+        return errors.New("Deposit data signature does not match public key and genesis fork version")
+    }
+
+    // Return
+    return nil
+
+}
+
+
+// Get the deposit data signing root with the deposit domain
+func getDepositDataSigningRoot(depositData DepositData, eth2Config beacon.Eth2Config) ([32]byte, error) {
+
+    // Get signing root
+    sr, err := ssz.SigningRoot(depositData)
+    if err != nil {
+        return [32]byte{}, err
+    }
+
+    // Get signing root with domain
+    return ssz.HashTreeRoot(signingRoot{
+        ObjectRoot: sr[:],
+        Domain: eth2types.Domain(eth2types.DomainDeposit, eth2Config.GenesisForkVersion, eth2types.ZeroGenesisValidatorsRoot),
+    })
+
+}
+